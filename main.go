@@ -5,10 +5,25 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/starkbaknet/moltbook-client/pkg/cli"
 	"github.com/starkbaknet/moltbook-client/pkg/tui"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "mock-server":
+			if err := runMockServer(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "mock-server: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if cli.IsCommand(os.Args[1]) {
+			os.Exit(cli.Run(os.Args[1:]))
+		}
+	}
+
 	p := tea.NewProgram(tui.NewModel(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
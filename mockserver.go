@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/starkbaknet/moltbook-client/pkg/moltbooktest"
+)
+
+// runMockServer serves a seeded in-memory Moltbook so the TUI can be driven
+// offline: MOLTBOOK_BASE_URL=<printed url> ./moltbook
+func runMockServer(args []string) error {
+	fs := flag.NewFlagSet("mock-server", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8787", "address to listen on")
+	wrapped := fs.Bool("wrapped", false, "nest response payloads under \"data\"")
+	empty := fs.Bool("empty", false, "start without seed data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var opts []moltbooktest.Option
+	if *wrapped {
+		opts = append(opts, moltbooktest.WithShape(moltbooktest.ShapeWrapped))
+	}
+	if !*empty {
+		opts = append(opts, moltbooktest.WithSeed())
+	}
+	fake := moltbooktest.New(opts...)
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Fake Moltbook listening on http://%s%s\n", ln.Addr(), moltbooktest.APIPrefix)
+	fmt.Printf("  export MOLTBOOK_BASE_URL=http://%s%s\n", ln.Addr(), moltbooktest.APIPrefix)
+	if !*empty {
+		fmt.Printf("  seeded agent \"molty\" has API key %s\n", moltbooktest.SeedAPIKey)
+	}
+	return http.Serve(ln, fake)
+}
@@ -0,0 +1,121 @@
+// Package agent runs an autonomous Moltbook agent: on every heartbeat it
+// polls the feeds and the agent's own threads, hands anything new to a
+// Responder, and carries out the actions it returns within the posting and
+// commenting cooldowns.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/safety"
+)
+
+// Responder decides how the agent reacts to what the runner observes. Each
+// item is delivered once, to exactly one method. Returning no actions is
+// fine; an error is logged and the item is not offered again, unless the
+// run was canceled.
+type Responder interface {
+	// OnNewPost is called for a post by someone else that appeared in a
+	// watched feed.
+	OnNewPost(ctx context.Context, ev Event) ([]Action, error)
+
+	// OnReplyToMe is called for a comment on one of the agent's posts, or a
+	// reply to one of its comments. ev.Comment is set.
+	OnReplyToMe(ctx context.Context, ev Event) ([]Action, error)
+
+	// OnMention is called for a post or comment that mentions @name but
+	// isn't a reply to the agent. ev.Comment is nil for posts.
+	OnMention(ctx context.Context, ev Event) ([]Action, error)
+}
+
+// EventKind names the Responder method an Event is delivered to.
+type EventKind string
+
+const (
+	EventNewPost   EventKind = "new_post"
+	EventReplyToMe EventKind = "reply_to_me"
+	EventMention   EventKind = "mention"
+)
+
+// Event is something the runner observed.
+type Event struct {
+	Kind    EventKind    `json:"event"`
+	Agent   string       `json:"agent"` // Name of the agent being run
+	Post    api.Post     `json:"post"`
+	Comment *api.Comment `json:"comment,omitempty"`
+
+	// Parent is the agent's own comment that Comment replies to, if any
+	Parent *api.Comment `json:"parent,omitempty"`
+
+	// Findings flags likely prompt injection in the other agent's text. With
+	// sanitize on, Post and Comment already carry the neutralized text.
+	Findings []safety.Finding `json:"findings,omitempty"`
+}
+
+// ActionKind says what an Action does.
+type ActionKind string
+
+const (
+	ActionComment ActionKind = "comment" // Comment on PostID, replying to ParentID if set
+	ActionPost    ActionKind = "post"    // New post in Submolt with Title and Content or URL
+	ActionUpvote  ActionKind = "upvote"  // Upvote CommentID if set, else PostID
+)
+
+// Action is something a Responder wants the agent to do.
+type Action struct {
+	Kind      ActionKind `json:"kind"`
+	PostID    string     `json:"post_id,omitempty"`
+	ParentID  string     `json:"parent_id,omitempty"`
+	CommentID string     `json:"comment_id,omitempty"`
+	Submolt   string     `json:"submolt,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	URL       string     `json:"url,omitempty"`
+
+	// Reason is logged with the action and never sent anywhere
+	Reason string `json:"reason,omitempty"`
+}
+
+// Validate reports actions that can't be carried out as given.
+func (a Action) Validate() error {
+	switch a.Kind {
+	case ActionComment:
+		if a.PostID == "" {
+			return errors.New("comment: post_id is required")
+		}
+		if strings.TrimSpace(a.Content) == "" {
+			return errors.New("comment: content is required")
+		}
+	case ActionPost:
+		if strings.TrimSpace(a.Title) == "" {
+			return errors.New("post: title is required")
+		}
+		if (strings.TrimSpace(a.Content) == "") == (a.URL == "") {
+			return errors.New("post: exactly one of content and url is required")
+		}
+		if a.URL != "" {
+			if err := api.ValidateLinkURL(a.URL); err != nil {
+				return fmt.Errorf("post: %w", err)
+			}
+		}
+	case ActionUpvote:
+		if a.PostID == "" && a.CommentID == "" {
+			return errors.New("upvote: post_id or comment_id is required")
+		}
+	default:
+		return fmt.Errorf("unknown action kind %q", a.Kind)
+	}
+	return nil
+}
+
+// NopResponder observes without acting, which together with the decision
+// log is useful for checking what a real responder would be offered.
+type NopResponder struct{}
+
+func (NopResponder) OnNewPost(context.Context, Event) ([]Action, error)   { return nil, nil }
+func (NopResponder) OnReplyToMe(context.Context, Event) ([]Action, error) { return nil, nil }
+func (NopResponder) OnMention(context.Context, Event) ([]Action, error)   { return nil, nil }
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the agent.yaml file:
+//
+//	heartbeat: 10m
+//	state_file: agent-state.json
+//	watch:
+//	  feed: true
+//	  personalized: true
+//	  own_posts: true
+//	post_cooldown: 30m
+//	comment_cooldown: 20s
+//	responder:
+//	  command: ["python3", "bot.py"]
+type Config struct {
+	// Heartbeat is the time between polls
+	Heartbeat time.Duration `yaml:"heartbeat"`
+
+	// StateFile remembers what has been seen across runs. Relative paths are
+	// resolved against the config file's directory.
+	StateFile string `yaml:"state_file"`
+
+	// DryRun prints intended actions instead of carrying them out, and
+	// leaves the state file untouched
+	DryRun bool `yaml:"dry_run"`
+
+	Watch Watch `yaml:"watch"`
+
+	// FeedSort and FeedLimit apply to every feed poll
+	FeedSort  string `yaml:"feed_sort"`
+	FeedLimit int    `yaml:"feed_limit"`
+
+	PostCooldown    time.Duration `yaml:"post_cooldown"`
+	CommentCooldown time.Duration `yaml:"comment_cooldown"`
+
+	// MaxActionsPerBeat caps how much the agent does per heartbeat
+	MaxActionsPerBeat int `yaml:"max_actions_per_beat"`
+
+	// Sanitize hands responders the neutralized text of posts and comments
+	// that look like prompt injection, see package safety. Findings are
+	// attached to the event either way.
+	Sanitize bool `yaml:"sanitize"`
+
+	// RespondToBacklog offers everything already there on the very first
+	// run. By default the first heartbeat only records what it sees.
+	RespondToBacklog bool `yaml:"respond_to_backlog"`
+
+	Responder ResponderConfig `yaml:"responder"`
+
+	// LogFormat is text or json
+	LogFormat string `yaml:"log_format"`
+}
+
+// Watch selects what the agent polls.
+type Watch struct {
+	Feed         bool `yaml:"feed"`
+	Personalized bool `yaml:"personalized"`
+	OwnPosts     bool `yaml:"own_posts"` // and threads the agent has commented in
+}
+
+// ResponderConfig picks the Responder used by `moltbook agent run`. Without
+// a command the agent only observes.
+type ResponderConfig struct {
+	// Command receives each Event as JSON on stdin and prints a JSON array
+	// of Actions on stdout
+	Command []string      `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// DefaultConfig matches Moltbook's own limits of one post per 30 minutes
+// and one comment per 20 seconds.
+func DefaultConfig() Config {
+	return Config{
+		Heartbeat:         10 * time.Minute,
+		StateFile:         "agent-state.json",
+		Watch:             Watch{Feed: true, Personalized: true, OwnPosts: true},
+		FeedSort:          "new",
+		FeedLimit:         25,
+		PostCooldown:      30 * time.Minute,
+		CommentCooldown:   20 * time.Second,
+		MaxActionsPerBeat: 5,
+		Sanitize:          true,
+		Responder:         ResponderConfig{Timeout: time.Minute},
+		LogFormat:         "text",
+	}
+}
+
+// LoadConfig reads path over the defaults. Unknown keys are an error so
+// typos don't silently fall back to a default.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.StateFile != "" && !filepath.IsAbs(cfg.StateFile) {
+		cfg.StateFile = filepath.Join(filepath.Dir(path), cfg.StateFile)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks values LoadConfig can't catch while decoding.
+func (c Config) Validate() error {
+	switch {
+	case c.Heartbeat < time.Second:
+		return errors.New("heartbeat must be at least 1s")
+	case c.FeedLimit <= 0:
+		return errors.New("feed_limit must be positive")
+	case c.PostCooldown < 0 || c.CommentCooldown < 0:
+		return errors.New("cooldowns can't be negative")
+	case c.MaxActionsPerBeat <= 0:
+		return errors.New("max_actions_per_beat must be positive")
+	case !c.Watch.Feed && !c.Watch.Personalized && !c.Watch.OwnPosts:
+		return errors.New("watch: nothing to poll")
+	}
+	switch c.FeedSort {
+	case "hot", "new", "top", "rising":
+	default:
+		return fmt.Errorf("unknown feed_sort %q", c.FeedSort)
+	}
+	switch c.LogFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unknown log_format %q", c.LogFormat)
+	}
+	return nil
+}
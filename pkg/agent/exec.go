@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// ExecResponder runs an external program per event, so responders can be
+// written in any language. The Event is written to its stdin as JSON; it
+// answers with a JSON array of Actions on stdout, or nothing at all.
+type ExecResponder struct {
+	Command []string
+	Timeout time.Duration // Per event; zero means no limit
+	Stderr  io.Writer     // Receives the program's stderr, e.g. for its own logs
+}
+
+func (r ExecResponder) OnNewPost(ctx context.Context, ev Event) ([]Action, error) {
+	return r.run(ctx, ev)
+}
+
+func (r ExecResponder) OnReplyToMe(ctx context.Context, ev Event) ([]Action, error) {
+	return r.run(ctx, ev)
+}
+
+func (r ExecResponder) OnMention(ctx context.Context, ev Event) ([]Action, error) {
+	return r.run(ctx, ev)
+}
+
+func (r ExecResponder) run(ctx context.Context, ev Event) ([]Action, error) {
+	if len(r.Command) == 0 {
+		return nil, errors.New("no responder command configured")
+	}
+	input, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, r.Command[0], r.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = r.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Command[0], err)
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var actions []Action
+	if err := json.Unmarshal(out, &actions); err != nil {
+		return nil, fmt.Errorf("%s: reading actions: %w", r.Command[0], err)
+	}
+	return actions, nil
+}
@@ -0,0 +1,505 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/safety"
+)
+
+// commentPageSize is how many of the newest comments are checked per thread.
+const commentPageSize = 100
+
+// Runner drives a Responder from a heartbeat. Create it with NewRunner.
+type Runner struct {
+	client    *api.Client
+	responder Responder
+	cfg       Config
+	log       *slog.Logger
+	out       io.Writer // Where dry runs print intended actions
+	now       func() time.Time
+
+	me      string
+	mention *regexp.Regexp // Matches @me, set with me
+	state   *State
+	fresh   bool // No state file yet
+}
+
+// NewRunner prepares a runner; nothing is fetched until Run or RunOnce.
+// Dry-run actions are printed to out.
+func NewRunner(client *api.Client, responder Responder, cfg Config, log *slog.Logger, out io.Writer) *Runner {
+	return &Runner{
+		client:    client,
+		responder: responder,
+		cfg:       cfg,
+		log:       log,
+		out:       out,
+		now:       time.Now,
+	}
+}
+
+// Run beats every cfg.Heartbeat until ctx is canceled. Failed heartbeats are
+// logged and retried on the next one, except for a rejected API key.
+func (r *Runner) Run(ctx context.Context) error {
+	if err := r.start(ctx); err != nil {
+		return err
+	}
+	for {
+		if err := r.Beat(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, api.ErrUnauthorized) {
+				return err
+			}
+			r.log.Error("heartbeat failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.cfg.Heartbeat):
+		}
+	}
+}
+
+// RunOnce runs a single heartbeat, e.g. from cron.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	if err := r.start(ctx); err != nil {
+		return err
+	}
+	return r.Beat(ctx)
+}
+
+func (r *Runner) start(ctx context.Context) error {
+	me, err := r.client.GetMe(ctx)
+	if err != nil {
+		return fmt.Errorf("looking up the agent: %w", err)
+	}
+	r.me = me.Name
+	r.mention = mentionPattern(me.Name)
+	r.state, r.fresh, err = LoadState(r.cfg.StateFile)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	r.log.Info("agent started", "agent", r.me, "heartbeat", r.cfg.Heartbeat, "dry_run", r.cfg.DryRun, "state_file", r.cfg.StateFile, "first_run", r.fresh)
+	return nil
+}
+
+// Beat polls once, offers everything new to the responder and carries out
+// its actions.
+func (r *Runner) Beat(ctx context.Context) error {
+	r.log.Info("heartbeat")
+	before := r.state.clone()
+	events, err := r.collect(ctx)
+	if err != nil {
+		// Offer the same items again next time
+		r.state = before
+		return err
+	}
+
+	if r.fresh && !r.cfg.RespondToBacklog {
+		r.log.Info("first run: recorded existing posts and comments without responding", "skipped_events", len(events))
+		r.fresh = false
+		return r.save()
+	}
+	r.fresh = false
+
+	budget := r.cfg.MaxActionsPerBeat
+	for i, ev := range events {
+		if budget == 0 {
+			// Offer the rest next heartbeat rather than dropping them
+			for _, ev := range events[i:] {
+				r.state.forget(ev)
+			}
+			r.log.Info("deferred events: max_actions_per_beat reached", "events", len(events)-i)
+			break
+		}
+		if ctx.Err() != nil {
+			return r.interrupt(ctx, events[i:])
+		}
+		ev = r.screen(ev)
+		actions, err := r.dispatch(ctx, ev)
+		attrs := eventAttrs(ev)
+		if err != nil {
+			if ctx.Err() != nil {
+				return r.interrupt(ctx, events[i:])
+			}
+			r.log.Warn("responder failed", append(attrs, "err", err)...)
+			continue
+		}
+		r.log.Info("responder decided", append(attrs, "actions", len(actions))...)
+		done := 0
+		for _, act := range actions {
+			if budget == 0 {
+				r.log.Info("skipped action: max_actions_per_beat reached", actionAttrs(act)...)
+				continue
+			}
+			budget--
+			if err := r.execute(ctx, act); err != nil {
+				if ctx.Err() != nil {
+					if done == 0 {
+						return r.interrupt(ctx, events[i:])
+					}
+					return r.interrupt(ctx, events[i+1:])
+				}
+				r.log.Warn("action failed", append(actionAttrs(act), "err", err)...)
+				continue
+			}
+			done++
+		}
+	}
+
+	r.state.prune(r.now())
+	return r.save()
+}
+
+// interrupt saves what a canceled beat got done, so its actions aren't
+// repeated next run. The events in rest weren't acted on and are offered
+// again.
+func (r *Runner) interrupt(ctx context.Context, rest []Event) error {
+	for _, ev := range rest {
+		r.state.forget(ev)
+	}
+	r.log.Info("heartbeat interrupted", "unhandled_events", len(rest))
+	if err := r.save(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (r *Runner) save() error {
+	if r.cfg.DryRun {
+		return nil // Dry runs must not hide anything from the real run
+	}
+	return r.state.Save(r.cfg.StateFile)
+}
+
+// screen checks the other agent's text for prompt injection before a
+// responder, and likely a model behind it, sees it.
+func (r *Runner) screen(ev Event) Event {
+	check := func(s *string) {
+		res := safety.Check(*s)
+		ev.Findings = append(ev.Findings, res.Findings...)
+		if r.cfg.Sanitize {
+			*s = res.Neutralized
+		}
+	}
+	check(&ev.Post.Title)
+	check(&ev.Post.Content)
+	if ev.Comment != nil {
+		c := *ev.Comment
+		check(&c.Content)
+		ev.Comment = &c
+	}
+	if len(ev.Findings) > 0 {
+		r.log.Warn("possible prompt injection", append(eventAttrs(ev), "findings", safety.Result{Findings: ev.Findings}.Summary(), "sanitized", r.cfg.Sanitize)...)
+	}
+	return ev
+}
+
+func (r *Runner) dispatch(ctx context.Context, ev Event) ([]Action, error) {
+	switch ev.Kind {
+	case EventReplyToMe:
+		return r.responder.OnReplyToMe(ctx, ev)
+	case EventMention:
+		return r.responder.OnMention(ctx, ev)
+	default:
+		return r.responder.OnNewPost(ctx, ev)
+	}
+}
+
+// collect polls everything watched and returns the events for items not
+// seen before, oldest first.
+func (r *Runner) collect(ctx context.Context) ([]Event, error) {
+	now := r.now()
+	var events []Event
+
+	var posts []api.Post
+	if r.cfg.Watch.Feed {
+		feed, err := r.client.GetFeed(ctx, r.cfg.FeedSort, r.cfg.FeedLimit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("polling feed: %w", err)
+		}
+		posts = append(posts, feed...)
+	}
+	if r.cfg.Watch.Personalized {
+		feed, err := r.client.GetPersonalizedFeed(ctx, r.cfg.FeedSort, r.cfg.FeedLimit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("polling personalized feed: %w", err)
+		}
+		posts = append(posts, feed...)
+	}
+	for _, p := range posts {
+		if !r.state.markPost(p.ID, now) {
+			continue
+		}
+		switch {
+		case p.Author.Name == r.me:
+			r.log.Debug("ignored own post", "post", p.ID)
+		case r.mentions(p.Title + "\n" + p.Content):
+			events = append(events, Event{Kind: EventMention, Agent: r.me, Post: p})
+		default:
+			events = append(events, Event{Kind: EventNewPost, Agent: r.me, Post: p})
+		}
+	}
+
+	if r.cfg.Watch.OwnPosts {
+		threads, err := r.threads(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range threads {
+			evs, err := r.commentEvents(ctx, p, now)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, evs...)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	return events, nil
+}
+
+// threads lists the agent's recent posts and the posts it commented on.
+func (r *Runner) threads(ctx context.Context) ([]api.Post, error) {
+	_, own, err := r.client.GetProfile(ctx, r.me)
+	if err != nil {
+		return nil, fmt.Errorf("polling own posts: %w", err)
+	}
+	seen := make(map[string]bool)
+	var threads []api.Post
+	for _, p := range own {
+		seen[p.ID] = true
+		r.state.markPost(p.ID, r.now())
+		threads = append(threads, p)
+	}
+	for id := range r.state.Watched {
+		if seen[id] {
+			continue
+		}
+		p, err := r.client.GetPost(ctx, id)
+		if errors.Is(err, api.ErrNotFound) {
+			r.log.Info("stopped watching deleted post", "post", id)
+			delete(r.state.Watched, id)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("polling watched post %s: %w", id, err)
+		}
+		threads = append(threads, *p)
+	}
+	return threads, nil
+}
+
+// commentEvents classifies the new comments on post.
+func (r *Runner) commentEvents(ctx context.Context, post api.Post, now time.Time) ([]Event, error) {
+	tree, err := r.client.GetComments(ctx, post.ID, api.CommentQuery{Sort: api.CommentSortNew, Limit: commentPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("polling comments on %s: %w", post.ID, err)
+	}
+	comments := flatten(tree)
+	byID := make(map[string]*api.Comment, len(comments))
+	for i := range comments {
+		byID[comments[i].ID] = &comments[i]
+	}
+
+	var events []Event
+	for i := range comments {
+		c := &comments[i]
+		if !r.state.markComment(c.ID, now) || c.Author.Name == r.me {
+			continue
+		}
+		parent := byID[c.ParentID]
+		switch {
+		case c.ParentID == "" && post.Author.Name == r.me:
+			events = append(events, Event{Kind: EventReplyToMe, Agent: r.me, Post: post, Comment: c})
+		case parent != nil && parent.Author.Name == r.me:
+			events = append(events, Event{Kind: EventReplyToMe, Agent: r.me, Post: post, Comment: c, Parent: parent})
+		case r.mentions(c.Content):
+			events = append(events, Event{Kind: EventMention, Agent: r.me, Post: post, Comment: c})
+		default:
+			r.log.Debug("ignored comment", "post", post.ID, "comment", c.ID, "author", c.Author.Name)
+		}
+	}
+	return events, nil
+}
+
+// execute carries out one action, honoring the cooldowns.
+func (r *Runner) execute(ctx context.Context, act Action) error {
+	if err := act.Validate(); err != nil {
+		return err
+	}
+	attrs := actionAttrs(act)
+
+	switch act.Kind {
+	case ActionPost:
+		if left := r.state.LastPost.Add(r.cfg.PostCooldown).Sub(r.now()); left > 0 {
+			r.log.Info("skipped action: post cooldown", append(attrs, "remaining", left.Round(time.Second))...)
+			return nil
+		}
+	case ActionComment:
+		if left := r.state.LastComment.Add(r.cfg.CommentCooldown).Sub(r.now()); left > 0 && !r.cfg.DryRun {
+			r.log.Info("waiting for comment cooldown", append(attrs, "remaining", left.Round(time.Second))...)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(left):
+			}
+		}
+	}
+
+	if r.cfg.DryRun {
+		fmt.Fprintln(r.out, "DRY RUN", describe(act))
+		r.log.Info("dry run: action not sent", attrs...)
+		r.recordAction(act)
+		return nil
+	}
+
+	// One key per action so transport retries can't double-post
+	callCtx := api.WithCallOptions(ctx, api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
+	var err error
+	switch act.Kind {
+	case ActionComment:
+		if act.ParentID != "" {
+			_, err = r.client.CreateReply(callCtx, act.PostID, act.ParentID, act.Content)
+		} else {
+			_, err = r.client.CreateComment(callCtx, act.PostID, act.Content)
+		}
+	case ActionPost:
+		submolt := act.Submolt
+		if submolt == "" {
+			submolt = "general"
+		}
+		if act.URL != "" {
+			_, err = r.client.CreateLinkPost(callCtx, submolt, act.Title, act.URL)
+		} else {
+			_, err = r.client.CreatePost(callCtx, submolt, act.Title, act.Content)
+		}
+	case ActionUpvote:
+		if act.CommentID != "" {
+			err = r.client.UpvoteComment(ctx, act.CommentID)
+		} else {
+			err = r.client.UpvotePost(ctx, act.PostID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	r.log.Info("action done", attrs...)
+	r.recordAction(act)
+	return nil
+}
+
+func (r *Runner) recordAction(act Action) {
+	now := r.now()
+	switch act.Kind {
+	case ActionPost:
+		r.state.LastPost = now
+	case ActionComment:
+		r.state.LastComment = now
+		r.state.watch(act.PostID, now)
+	}
+}
+
+// mentionPattern matches @name as a whole word, or nothing for an empty name.
+func mentionPattern(name string) *regexp.Regexp {
+	if name == "" {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)(^|[^\w@])@` + regexp.QuoteMeta(name) + `\b`)
+}
+
+// mentions reports whether text mentions the agent.
+func (r *Runner) mentions(text string) bool {
+	return r.mention != nil && r.mention.MatchString(text)
+}
+
+// flatten lists every comment in a possibly nested thread, filling in
+// ParentID from the nesting.
+func flatten(comments []api.Comment) []api.Comment {
+	var out []api.Comment
+	var walk func(cs []api.Comment, parent string)
+	walk = func(cs []api.Comment, parent string) {
+		for _, c := range cs {
+			if c.ParentID == "" {
+				c.ParentID = parent
+			}
+			replies := c.Replies
+			c.Replies = nil
+			out = append(out, c)
+			walk(replies, c.ID)
+		}
+	}
+	walk(comments, "")
+	return out
+}
+
+func eventTime(ev Event) time.Time {
+	if ev.Comment != nil {
+		return ev.Comment.CreatedAt
+	}
+	return ev.Post.CreatedAt
+}
+
+func eventAttrs(ev Event) []any {
+	attrs := []any{"event", ev.Kind, "post", ev.Post.ID}
+	if ev.Comment != nil {
+		attrs = append(attrs, "comment", ev.Comment.ID, "author", ev.Comment.Author.Name)
+	} else {
+		attrs = append(attrs, "author", ev.Post.Author.Name)
+	}
+	return attrs
+}
+
+func actionAttrs(act Action) []any {
+	attrs := []any{"action", act.Kind}
+	for _, kv := range [][2]string{
+		{"post", act.PostID},
+		{"parent", act.ParentID},
+		{"comment", act.CommentID},
+		{"submolt", act.Submolt},
+		{"reason", act.Reason},
+	} {
+		if kv[1] != "" {
+			attrs = append(attrs, kv[0], kv[1])
+		}
+	}
+	return attrs
+}
+
+// describe renders an action for dry-run output.
+func describe(act Action) string {
+	switch act.Kind {
+	case ActionComment:
+		if act.ParentID != "" {
+			return fmt.Sprintf("reply on %s to %s: %q", act.PostID, act.ParentID, act.Content)
+		}
+		return fmt.Sprintf("comment on %s: %q", act.PostID, act.Content)
+	case ActionPost:
+		submolt := act.Submolt
+		if submolt == "" {
+			submolt = "general"
+		}
+		body := act.Content
+		if act.URL != "" {
+			body = act.URL
+		}
+		return fmt.Sprintf("post to m/%s %q: %q", submolt, act.Title, body)
+	case ActionUpvote:
+		if act.CommentID != "" {
+			return "upvote comment " + act.CommentID
+		}
+		return "upvote post " + act.PostID
+	}
+	return string(act.Kind)
+}
@@ -0,0 +1,289 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/moltbooktest"
+)
+
+// funcResponder hands every event to one function.
+type funcResponder func(ctx context.Context, ev Event) ([]Action, error)
+
+func (f funcResponder) OnNewPost(ctx context.Context, ev Event) ([]Action, error) {
+	return f(ctx, ev)
+}
+
+func (f funcResponder) OnReplyToMe(ctx context.Context, ev Event) ([]Action, error) {
+	return f(ctx, ev)
+}
+
+func (f funcResponder) OnMention(ctx context.Context, ev Event) ([]Action, error) {
+	return f(ctx, ev)
+}
+
+// recorder lists the posts offered and answers each with act, if set.
+type recorder struct {
+	offered []string
+	act     func(ev Event) []Action
+}
+
+func (rec *recorder) responder() Responder {
+	return funcResponder(func(ctx context.Context, ev Event) ([]Action, error) {
+		rec.offered = append(rec.offered, ev.Post.ID)
+		if rec.act == nil {
+			return nil, nil
+		}
+		return rec.act(ev), nil
+	})
+}
+
+func comment(ev Event) []Action {
+	return []Action{{Kind: ActionComment, PostID: ev.Post.ID, Content: "Interesting!"}}
+}
+
+func upvote(ev Event) []Action {
+	return []Action{{Kind: ActionUpvote, PostID: ev.Post.ID}}
+}
+
+func newTestServer(t *testing.T) *moltbooktest.Server {
+	t.Helper()
+	srv := moltbooktest.NewServer(moltbooktest.WithSeed())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testConfig polls only the global feed, which the seed fills with four
+// posts by other agents and one by molty.
+func testConfig(t *testing.T) Config {
+	cfg := DefaultConfig()
+	cfg.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.Watch = Watch{Feed: true}
+	return cfg
+}
+
+func newTestRunner(srv *moltbooktest.Server, cfg Config, resp Responder, out io.Writer) *Runner {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRunner(srv.Client(moltbooktest.SeedAPIKey), resp, cfg, log, out)
+}
+
+func TestFirstRunSkipsBacklog(t *testing.T) {
+	tests := []struct {
+		name    string
+		backlog bool
+		want    int
+	}{
+		{"default", false, 0},
+		{"respond_to_backlog", true, 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			cfg := testConfig(t)
+			cfg.RespondToBacklog = tc.backlog
+			rec := &recorder{}
+			r := newTestRunner(srv, cfg, rec.responder(), io.Discard)
+			ctx := context.Background()
+
+			if err := r.RunOnce(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.offered) != tc.want {
+				t.Errorf("first run offered %d posts, want %d", len(rec.offered), tc.want)
+			}
+			if _, err := os.Stat(cfg.StateFile); err != nil {
+				t.Errorf("first run didn't save state: %v", err)
+			}
+
+			rec.offered = nil
+			p := srv.AddPost("clawdia", "general", "Fresh", "Posted after the first run.")
+			if err := r.Beat(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.offered) != 1 || rec.offered[0] != p.ID {
+				t.Errorf("second beat offered %v, want only %s", rec.offered, p.ID)
+			}
+		})
+	}
+}
+
+func TestMaxActionsPerBeatDefers(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := testConfig(t)
+	cfg.RespondToBacklog = true
+	cfg.MaxActionsPerBeat = 2
+	rec := &recorder{act: upvote}
+	r := newTestRunner(srv, cfg, rec.responder(), io.Discard)
+	ctx := context.Background()
+
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	offered := make(map[string]bool)
+	for i, want := range []int{2, 2, 0} {
+		if i > 0 {
+			if err := r.Beat(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(rec.offered) != want {
+			t.Errorf("beat %d offered %d posts, want %d", i+1, len(rec.offered), want)
+		}
+		for _, id := range rec.offered {
+			if offered[id] {
+				t.Errorf("beat %d offered %s again", i+1, id)
+			}
+			offered[id] = true
+		}
+		rec.offered = nil
+	}
+}
+
+func TestCommentCooldown(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := testConfig(t)
+	cfg.CommentCooldown = 300 * time.Millisecond
+	rec := &recorder{act: comment}
+	r := newTestRunner(srv, cfg, rec.responder(), io.Discard)
+	ctx := context.Background()
+
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r.state.LastComment = time.Now()
+	p := srv.AddPost("clawdia", "general", "Fresh", "Posted after the first run.")
+
+	start := time.Now()
+	if err := r.Beat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("commented after %v, want the 300ms cooldown waited out", elapsed)
+	}
+	if n := len(srv.Comments(p.ID)); n != 1 {
+		t.Errorf("%d comments on the new post, want 1", n)
+	}
+}
+
+func TestDryRunDoesNotSave(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := testConfig(t)
+	cfg.DryRun = true
+	cfg.RespondToBacklog = true
+	rec := &recorder{act: comment}
+	var out bytes.Buffer
+	r := newTestRunner(srv, cfg, rec.responder(), &out)
+
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "DRY RUN comment on "); n != 4 {
+		t.Errorf("printed %d dry-run comments, want 4:\n%s", n, out.String())
+	}
+	for _, p := range srv.Posts() {
+		for _, c := range srv.Comments(p.ID) {
+			if c.Content == "Interesting!" {
+				t.Errorf("dry run commented on %s", p.ID)
+			}
+		}
+	}
+	if _, err := os.Stat(cfg.StateFile); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("dry run wrote the state file: %v", err)
+	}
+}
+
+func TestCancelMidBeatKeepsState(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := testConfig(t)
+	cfg.RespondToBacklog = true
+	cfg.CommentCooldown = time.Hour
+	rec := &recorder{act: comment}
+	r := newTestRunner(srv, cfg, rec.responder(), io.Discard)
+
+	// The first comment goes out, the second waits on the cooldown until
+	// the deadline cuts the beat short
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := r.RunOnce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if len(rec.offered) != 2 {
+		t.Fatalf("offered %d posts before the deadline, want 2", len(rec.offered))
+	}
+	done := rec.offered[0]
+
+	s, fresh, err := LoadState(cfg.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh {
+		t.Fatal("canceled beat didn't save state")
+	}
+	if s.LastComment.IsZero() {
+		t.Error("LastComment wasn't saved")
+	}
+	if _, ok := s.Watched[done]; !ok {
+		t.Errorf("commented post %s isn't watched", done)
+	}
+	if _, ok := s.Posts[rec.offered[1]]; ok {
+		t.Errorf("post %s, interrupted before its comment, was saved as seen", rec.offered[1])
+	}
+
+	// The next run picks up where the canceled one stopped
+	next := &recorder{}
+	r = newTestRunner(srv, cfg, next.responder(), io.Discard)
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.offered) != 3 {
+		t.Errorf("next run offered %d posts, want the 3 not acted on", len(next.offered))
+	}
+	for _, id := range next.offered {
+		if id == done {
+			t.Errorf("next run offered %s, which was already commented on", done)
+		}
+	}
+}
+
+func TestCollectFailureRestoresState(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := testConfig(t)
+	cfg.Watch.OwnPosts = true
+	rec := &recorder{}
+	r := newTestRunner(srv, cfg, rec.responder(), io.Discard)
+	ctx := context.Background()
+
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	p := srv.AddPost("clawdia", "general", "Fresh", "Posted after the first run.")
+
+	// The feed is polled, then the own posts fail
+	srv.Inject(moltbooktest.Failure{Method: http.MethodGet, Path: "/agents/profile", Status: http.StatusBadRequest, Message: "Injected"})
+	if err := r.Beat(ctx); err == nil {
+		t.Fatal("want the injected failure")
+	}
+	if len(rec.offered) != 0 {
+		t.Errorf("failed beat offered %v", rec.offered)
+	}
+	if _, ok := r.state.Posts[p.ID]; ok {
+		t.Error("failed beat kept the new post as seen")
+	}
+
+	if err := r.Beat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.offered) != 1 || rec.offered[0] != p.ID {
+		t.Errorf("next beat offered %v, want only %s", rec.offered, p.ID)
+	}
+}
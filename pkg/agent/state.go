@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// seenTTL is how long seen IDs are remembered; feeds don't resurface
+	// posts that old
+	seenTTL = 14 * 24 * time.Hour
+
+	// watchTTL and maxWatched bound the threads polled for replies
+	watchTTL   = 7 * 24 * time.Hour
+	maxWatched = 50
+)
+
+// State is what the runner remembers between heartbeats and runs.
+type State struct {
+	Posts    map[string]time.Time `json:"posts"`    // Post ID -> first seen
+	Comments map[string]time.Time `json:"comments"` // Comment ID -> first seen
+
+	// Watched are other agents' posts the agent commented on, polled for
+	// replies alongside its own posts. Post ID -> last commented.
+	Watched map[string]time.Time `json:"watched"`
+
+	LastPost    time.Time `json:"last_post"`
+	LastComment time.Time `json:"last_comment"`
+}
+
+func newState() *State {
+	return &State{
+		Posts:    make(map[string]time.Time),
+		Comments: make(map[string]time.Time),
+		Watched:  make(map[string]time.Time),
+	}
+}
+
+// LoadState reads path. A missing file yields an empty state and fresh
+// set to true.
+func LoadState(path string) (s *State, fresh bool, err error) {
+	s = newState()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, false, err
+	}
+	// Tolerate hand-edited files with missing sections
+	if s.Posts == nil {
+		s.Posts = make(map[string]time.Time)
+	}
+	if s.Comments == nil {
+		s.Comments = make(map[string]time.Time)
+	}
+	if s.Watched == nil {
+		s.Watched = make(map[string]time.Time)
+	}
+	return s, false, nil
+}
+
+// Save writes the state atomically, so a crash mid-write can't lose it.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// clone copies s, so a heartbeat that fails halfway can be undone.
+func (s *State) clone() *State {
+	c := *s
+	c.Posts = maps.Clone(s.Posts)
+	c.Comments = maps.Clone(s.Comments)
+	c.Watched = maps.Clone(s.Watched)
+	return &c
+}
+
+// markPost records id, reporting whether it was new.
+func (s *State) markPost(id string, now time.Time) bool {
+	if _, ok := s.Posts[id]; ok {
+		return false
+	}
+	s.Posts[id] = now
+	return true
+}
+
+// markComment records id, reporting whether it was new.
+func (s *State) markComment(id string, now time.Time) bool {
+	if _, ok := s.Comments[id]; ok {
+		return false
+	}
+	s.Comments[id] = now
+	return true
+}
+
+// forget unmarks the item behind ev so the next heartbeat offers it again.
+func (s *State) forget(ev Event) {
+	if ev.Comment != nil {
+		delete(s.Comments, ev.Comment.ID)
+		return
+	}
+	delete(s.Posts, ev.Post.ID)
+}
+
+// watch adds postID to the threads polled for replies, dropping the least
+// recently active one past maxWatched.
+func (s *State) watch(postID string, now time.Time) {
+	s.Watched[postID] = now
+	for len(s.Watched) > maxWatched {
+		var oldest string
+		for id, t := range s.Watched {
+			if oldest == "" || t.Before(s.Watched[oldest]) {
+				oldest = id
+			}
+		}
+		delete(s.Watched, oldest)
+	}
+}
+
+// prune forgets entries old enough not to matter any more.
+func (s *State) prune(now time.Time) {
+	for id, t := range s.Posts {
+		if now.Sub(t) > seenTTL {
+			delete(s.Posts, id)
+		}
+	}
+	for id, t := range s.Comments {
+		if now.Sub(t) > seenTTL {
+			delete(s.Comments, id)
+		}
+	}
+	for id, t := range s.Watched {
+		if now.Sub(t) > watchTTL {
+			delete(s.Watched, id)
+		}
+	}
+}
@@ -1,40 +1,69 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
+// BaseURL is the production API, used unless WithBaseURL says otherwise.
 const BaseURL = "https://www.moltbook.com/api/v1"
 
+const defaultUserAgent = "moltbook-go-client/1.0"
+
 type Client struct {
 	restClient *resty.Client
 	APIKey     string
+	limiter    *rateLimiter
+	retry      RetryPolicy
 }
 
-func NewClient(apiKey string) *Client {
-	c := resty.New()
-	c.SetBaseURL(BaseURL)
-	c.SetTimeout(60 * time.Second)
-	// Add retry logic for stability
-	c.SetRetryCount(3)
-	c.SetRetryWaitTime(1 * time.Second)
-	c.SetRetryMaxWaitTime(5 * time.Second)
-	
-	c.SetHeader("User-Agent", "moltbook-go-client/1.0")
-	
+func NewClient(apiKey string, opts ...Option) *Client {
+	o := clientOptions{
+		baseURL:   BaseURL,
+		userAgent: defaultUserAgent,
+		retry:     DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var c *resty.Client
+	if o.httpClient != nil {
+		// A copy, so neither resty nor WithTimeout changes the caller's client
+		hc := *o.httpClient
+		c = resty.NewWithClient(&hc)
+	} else {
+		c = resty.New()
+		c.SetTimeout(60 * time.Second)
+	}
+	// Only override a caller-supplied http.Client's timeout when asked to
+	if o.timeout > 0 {
+		c.SetTimeout(o.timeout)
+	}
+	c.SetBaseURL(o.baseURL)
+	// Retries are handled by doWithRetry so writes aren't blindly re-sent
+
+	c.SetHeader("User-Agent", o.userAgent)
+
 	if apiKey != "" {
 		c.SetAuthToken(apiKey)
 		// Fallback for some older platform versions
 		c.SetHeader("X-API-Key", apiKey)
 	}
-	
+
 	return &Client{
 		restClient: c,
 		APIKey:     apiKey,
+		limiter:    newRateLimiter(),
+		retry:      o.retry,
 	}
 }
 
@@ -47,10 +76,15 @@ type FlexibleResponse struct {
 	// Additional root-level fields some endpoints use
 	Agent       *Agent  `json:"agent"`
 	Posts       []Post  `json:"posts"`
-	Results     []Post  `json:"results"`
+	Results     []SearchResult `json:"results"`
 	Comments    []Comment `json:"comments"`
 	Status      string    `json:"status"`
 	RecentPosts []Post    `json:"recentPosts"`
+	Submolts    []Submolt `json:"submolts"`
+	Submolt     *Submolt  `json:"submolt"`
+	Post        *Post     `json:"post"`
+	Comment     *Comment  `json:"comment"`
+	Agents      []Agent   `json:"agents"`
 }
 
 type Agent struct {
@@ -60,6 +94,7 @@ type Agent struct {
 	FollowerCount    int    `json:"follower_count"`
 	FollowingCount   int    `json:"following_count"`
 	IsClaimed        bool   `json:"is_claimed"`
+	IsFollowing      bool   `json:"is_following,omitempty"` // Whether the caller follows this agent
 	APIKey           string `json:"api_key,omitempty"`
 	ClaimURL         string `json:"claim_url,omitempty"`
 	VerificationCode string `json:"verification_code,omitempty"`
@@ -84,6 +119,11 @@ type Post struct {
 	Similarity float64 `json:"similarity,omitempty"`
 }
 
+// IsLink reports whether the post shares a URL rather than (only) text.
+func (p Post) IsLink() bool {
+	return p.URL != "" || p.Type == "link"
+}
+
 type Comment struct {
 	ID        string    `json:"id"`
 	Content   string    `json:"content"`
@@ -93,10 +133,36 @@ type Comment struct {
 	Upvotes   int       `json:"upvotes"`
 	Downvotes int       `json:"downvotes"`
 	CreatedAt time.Time `json:"created_at"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Replies   []Comment `json:"replies,omitempty"`
 }
 
-func (c *Client) request(method, path string, body interface{}, params map[string]string) (*FlexibleResponse, error) {
-	req := c.restClient.R()
+func (c *Client) request(ctx context.Context, method, path string, body interface{}, params map[string]string) (*FlexibleResponse, error) {
+	class := classifyEndpoint(method, path)
+	opts := callOptionsFrom(ctx)
+	// Only reads are safe to delay or replay without the caller knowing
+	idempotent := method == "GET"
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx, class, idempotent); err != nil {
+			return nil, err
+		}
+		res, err := c.doWithRetry(ctx, method, path, body, params, opts)
+		var rl *RateLimitError
+		if errors.As(err, &rl) {
+			c.limiter.cooldown(class, rl.RetryAfter)
+			if idempotent && attempt == 0 && rl.RetryAfter <= maxAutoWait {
+				continue
+			}
+		} else if class != ClassReads && rejected(err) {
+			c.limiter.refund(class)
+		}
+		return res, err
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, params map[string]string, idempotencyKey string) (*FlexibleResponse, error) {
+	req := c.restClient.R().SetContext(ctx)
 	if body != nil {
 		req.SetBody(body)
 	}
@@ -107,57 +173,64 @@ func (c *Client) request(method, path string, body interface{}, params map[strin
 		req.SetAuthToken(c.APIKey)
 		req.SetHeader("X-API-Key", c.APIKey)
 	}
+	if idempotencyKey != "" {
+		req.SetHeader("Idempotency-Key", idempotencyKey)
+	}
 
 	var res FlexibleResponse
 	resp, err := req.Execute(method, path)
 	if err != nil {
-		return nil, fmt.Errorf("network error: %w", err)
+		return nil, &NetworkError{Err: err}
 	}
 
 	// Handle Rate Limiting explicitly
 	if resp.StatusCode() == 429 {
 		var rateRes struct {
-			Error string `json:"error"`
-			Hint  string `json:"hint"`
-			RetryAfterSeconds int `json:"retry_after_seconds"`
-			RetryAfterMinutes int `json:"retry_after_minutes"`
+			Error             string `json:"error"`
+			Hint              string `json:"hint"`
+			RetryAfterSeconds int    `json:"retry_after_seconds"`
+			RetryAfterMinutes int    `json:"retry_after_minutes"`
 		}
 		json.Unmarshal(resp.Body(), &rateRes)
-		msg := "Rate limit exceeded"
-		if rateRes.Hint != "" {
-			msg += ": " + rateRes.Hint
-		} else if rateRes.Error != "" {
-			msg += ": " + rateRes.Error
+		retryAfter := time.Duration(rateRes.RetryAfterSeconds) * time.Second
+		if retryAfter == 0 {
+			retryAfter = time.Duration(rateRes.RetryAfterMinutes) * time.Minute
+		}
+		if retryAfter == 0 {
+			if secs, err := strconv.Atoi(resp.Header().Get("Retry-After")); err == nil {
+				retryAfter = time.Duration(secs) * time.Second
+			}
+		}
+		return nil, &RateLimitError{
+			RetryAfter: retryAfter,
+			RetryAt:    time.Now().Add(retryAfter),
+			Message:    rateRes.Error,
+			Hint:       rateRes.Hint,
 		}
-		return nil, fmt.Errorf("%s (Retry after %d seconds)", msg, rateRes.RetryAfterSeconds)
 	}
 
 	// Try to parse the response as a FlexibleResponse
 	if err := json.Unmarshal(resp.Body(), &res); err != nil {
 		if !resp.IsSuccess() {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode(), resp.String())
+			return nil, &APIError{StatusCode: resp.StatusCode(), Message: strings.TrimSpace(resp.String())}
 		}
 		return nil, fmt.Errorf("failed to parse JSON (%d): %v", resp.StatusCode(), err)
 	}
 
 	if !res.Success {
 		if res.Error != "" {
-			msg := res.Error
-			if res.Hint != "" {
-				msg += fmt.Sprintf(" (Hint: %s)", res.Hint)
-			}
-			return &res, fmt.Errorf("%s", msg)
+			return &res, &APIError{StatusCode: resp.StatusCode(), Message: res.Error, Hint: res.Hint}
 		}
 		if !resp.IsSuccess() {
-			return &res, fmt.Errorf("request failed: %d", resp.StatusCode())
+			return &res, &APIError{StatusCode: resp.StatusCode()}
 		}
 	}
 
 	return &res, nil
 }
 
-func (c *Client) Register(name, description string) (*Agent, error) {
-	res, err := c.request("POST", "/agents/register", map[string]string{
+func (c *Client) Register(ctx context.Context, name, description string) (*Agent, error) {
+	res, err := c.request(ctx, "POST", "/agents/register", map[string]string{
 		"name":        name,
 		"description": description,
 	}, nil)
@@ -179,14 +252,14 @@ func (c *Client) Register(name, description string) (*Agent, error) {
 	return nil, fmt.Errorf("failed to find agent in response")
 }
 
-func (c *Client) GetFeed(sort string, limit, offset int) ([]Post, error) {
+func (c *Client) GetFeed(ctx context.Context, sort string, limit, offset int) ([]Post, error) {
 	params := map[string]string{
 		"sort":   sort,
 		"limit":  fmt.Sprintf("%d", limit),
 		"offset": fmt.Sprintf("%d", offset),
 	}
 	
-	res, err := c.request("GET", "/posts", nil, params)
+	res, err := c.request(ctx, "GET", "/posts", nil, params)
 	if err != nil {
 		return nil, err 
 	}
@@ -206,19 +279,25 @@ func (c *Client) GetFeed(sort string, limit, offset int) ([]Post, error) {
 }
 
 // Added this new method support
-func (c *Client) GetSubmoltFeed(submolt, sort string, limit int) ([]Post, error) {
+func (c *Client) GetSubmoltFeed(ctx context.Context, submolt, sort string, limit, offset int) ([]Post, error) {
 	if limit == 0 { limit = 20 }
 	path := fmt.Sprintf("/submolts/%s/feed", submolt)
-	res, err := c.request("GET", path, nil, map[string]string{
-		"sort":  sort,
-		"limit": fmt.Sprintf("%d", limit),
+	res, err := c.request(ctx, "GET", path, nil, map[string]string{
+		"sort":   sort,
+		"limit":  fmt.Sprintf("%d", limit),
+		"offset": fmt.Sprintf("%d", offset),
 	})
 	if err != nil {
+		// No point falling back if the caller gave up
+		if ctx.Err() != nil {
+			return nil, err
+		}
 		// Fallback to query param if convenience endpoint fails
-		res, err = c.request("GET", "/posts", nil, map[string]string{
+		res, err = c.request(ctx, "GET", "/posts", nil, map[string]string{
 			"submolt": submolt,
 			"sort":    sort,
 			"limit":   fmt.Sprintf("%d", limit),
+			"offset":  fmt.Sprintf("%d", offset),
 		})
 		if err != nil {
 			return nil, err
@@ -239,9 +318,9 @@ func (c *Client) GetSubmoltFeed(submolt, sort string, limit int) ([]Post, error)
 	return []Post{}, nil
 }
 
-func (c *Client) GetPersonalizedFeed(sort string, limit, offset int) ([]Post, error) {
+func (c *Client) GetPersonalizedFeed(ctx context.Context, sort string, limit, offset int) ([]Post, error) {
 	if limit == 0 { limit = 20 }
-	res, err := c.request("GET", "/feed", nil, map[string]string{
+	res, err := c.request(ctx, "GET", "/feed", nil, map[string]string{
 		"sort":   sort,
 		"limit":  fmt.Sprintf("%d", limit),
 		"offset": fmt.Sprintf("%d", offset),
@@ -264,50 +343,109 @@ func (c *Client) GetPersonalizedFeed(sort string, limit, offset int) ([]Post, er
 	return res.Posts, nil
 }
 
-func (c *Client) Search(query string, searchType string) ([]Post, error) {
-	res, err := c.request("GET", "/search", nil, map[string]string{
-		"q":    query,
-		"type": searchType,
-	})
+// CreatePost publishes a text post. The returned post is nil when the server
+// doesn't echo it back.
+func (c *Client) CreatePost(ctx context.Context, submolt, title, content string) (*Post, error) {
+	res, err := c.request(ctx, "POST", "/posts", map[string]string{
+		"submolt": submolt,
+		"title":   title,
+		"content": content,
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
+	return postFrom(res), nil
+}
 
-	if len(res.Results) > 0 {
-		return res.Results, nil
+// CreateLinkPost shares linkURL, which must be an absolute http(s) URL.
+// Like CreatePost, the returned post may be nil.
+func (c *Client) CreateLinkPost(ctx context.Context, submolt, title, linkURL string) (*Post, error) {
+	if err := ValidateLinkURL(linkURL); err != nil {
+		return nil, err
 	}
+	res, err := c.request(ctx, "POST", "/posts", map[string]string{
+		"submolt": submolt,
+		"title":   title,
+		"url":     linkURL,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return postFrom(res), nil
+}
 
-	var data struct {
-		Results []Post `json:"results"`
+// ValidateLinkURL checks that raw is something worth posting as a link.
+func ValidateLinkURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("invalid URL: must start with http:// or https://")
 	}
-	if err := json.Unmarshal(res.Data, &data); err == nil && len(data.Results) > 0 {
-		return data.Results, nil
+	if u.Host == "" {
+		return errors.New("invalid URL: missing host")
+	}
+	return nil
+}
+
+func (c *Client) GetPost(ctx context.Context, postID string) (*Post, error) {
+	res, err := c.request(ctx, "GET", fmt.Sprintf("/posts/%s", postID), nil, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return res.Results, nil
+	if post := postFrom(res); post != nil {
+		return post, nil
+	}
+	return nil, fmt.Errorf("could not find post in response")
 }
 
-func (c *Client) CreatePost(submolt, title, content string) error {
-	_, err := c.request("POST", "/posts", map[string]string{
-		"submolt": submolt,
-		"title":   title,
-		"content": content,
-	}, nil)
-	return err
+// postFrom finds the post in a response, at the root or under data.
+func postFrom(res *FlexibleResponse) *Post {
+	if res.Post != nil {
+		return res.Post
+	}
+
+	var data struct {
+		Post Post `json:"post"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && data.Post.ID != "" {
+		return &data.Post
+	}
+	return nil
 }
 
-func (c *Client) DeletePost(postID string) error {
-	_, err := c.request("DELETE", fmt.Sprintf("/posts/%s", postID), nil, nil)
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	_, err := c.request(ctx, "DELETE", fmt.Sprintf("/posts/%s", postID), nil, nil)
 	return err
 }
 
-func (c *Client) UpvotePost(postID string) error {
-	_, err := c.request("POST", fmt.Sprintf("/posts/%s/upvote", postID), map[string]string{}, nil)
-	return err
+// Comment sort orders accepted by CommentQuery.
+const (
+	CommentSortTop           = "top"
+	CommentSortNew           = "new"
+	CommentSortControversial = "controversial"
+)
+
+// CommentQuery pages and orders GetComments. The zero value fetches the
+// whole thread in the server's default order.
+type CommentQuery struct {
+	Sort   string
+	Limit  int
+	Offset int
 }
 
-func (c *Client) GetComments(postID string) ([]Comment, error) {
-	res, err := c.request("GET", fmt.Sprintf("/posts/%s/comments", postID), nil, nil)
+func (c *Client) GetComments(ctx context.Context, postID string, q CommentQuery) ([]Comment, error) {
+	params := map[string]string{}
+	if q.Sort != "" {
+		params["sort"] = q.Sort
+	}
+	if q.Limit > 0 {
+		params["limit"] = fmt.Sprintf("%d", q.Limit)
+		params["offset"] = fmt.Sprintf("%d", q.Offset)
+	}
+	res, err := c.request(ctx, "GET", fmt.Sprintf("/posts/%s/comments", postID), nil, params)
 	if err != nil {
 		return nil, err
 	}
@@ -315,6 +453,10 @@ func (c *Client) GetComments(postID string) ([]Comment, error) {
 	if len(res.Comments) > 0 {
 		return res.Comments, nil
 	}
+	// Unwrapped response with an empty thread
+	if len(res.Data) == 0 && res.Comments != nil {
+		return res.Comments, nil
+	}
 
 	var data struct {
 		Comments []Comment `json:"comments"`
@@ -326,8 +468,8 @@ func (c *Client) GetComments(postID string) ([]Comment, error) {
 	return nil, fmt.Errorf("could not find comments in response")
 }
 
-func (c *Client) GetMe() (*Agent, error) {
-	res, err := c.request("GET", "/agents/me", nil, nil)
+func (c *Client) GetMe(ctx context.Context) (*Agent, error) {
+	res, err := c.request(ctx, "GET", "/agents/me", nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -346,8 +488,8 @@ func (c *Client) GetMe() (*Agent, error) {
 	return nil, fmt.Errorf("could not find agent in response")
 }
 
-func (c *Client) GetProfile(name string) (*Agent, []Post, error) {
-	res, err := c.request("GET", "/agents/profile", nil, map[string]string{
+func (c *Client) GetProfile(ctx context.Context, name string) (*Agent, []Post, error) {
+	res, err := c.request(ctx, "GET", "/agents/profile", nil, map[string]string{
 		"name": name,
 	})
 	if err != nil {
@@ -382,8 +524,8 @@ func (c *Client) GetProfile(name string) (*Agent, []Post, error) {
 	return agent, posts, nil
 }
 
-func (c *Client) GetStatus() (string, error) {
-	res, err := c.request("GET", "/agents/status", nil, nil)
+func (c *Client) GetStatus(ctx context.Context) (string, error) {
+	res, err := c.request(ctx, "GET", "/agents/status", nil, nil)
 	if err != nil {
 		return "", err
 	}
@@ -402,28 +544,62 @@ func (c *Client) GetStatus() (string, error) {
 	return "", fmt.Errorf("could not find status in response")
 }
 
-func (c *Client) Follow(name string) error {
-	_, err := c.request("POST", fmt.Sprintf("/agents/%s/follow", name), nil, nil)
+func (c *Client) Follow(ctx context.Context, name string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/agents/%s/follow", name), nil, nil)
 	return err
 }
 
-func (c *Client) Unfollow(name string) error {
-	_, err := c.request("DELETE", fmt.Sprintf("/agents/%s/follow", name), nil, nil)
+func (c *Client) Unfollow(ctx context.Context, name string) error {
+	_, err := c.request(ctx, "DELETE", fmt.Sprintf("/agents/%s/follow", name), nil, nil)
 	return err
 }
 
-func (c *Client) Subscribe(submolt string) error {
-	_, err := c.request("POST", fmt.Sprintf("/submolts/%s/subscribe", submolt), nil, nil)
+// ListFollowers returns a page of the agents following name.
+func (c *Client) ListFollowers(ctx context.Context, name string, limit, offset int) ([]Agent, error) {
+	return c.listAgents(ctx, fmt.Sprintf("/agents/%s/followers", name), limit, offset)
+}
+
+// ListFollowing returns a page of the agents name follows.
+func (c *Client) ListFollowing(ctx context.Context, name string, limit, offset int) ([]Agent, error) {
+	return c.listAgents(ctx, fmt.Sprintf("/agents/%s/following", name), limit, offset)
+}
+
+func (c *Client) listAgents(ctx context.Context, path string, limit, offset int) ([]Agent, error) {
+	if limit == 0 { limit = 20 }
+	res, err := c.request(ctx, "GET", path, nil, map[string]string{
+		"limit":  fmt.Sprintf("%d", limit),
+		"offset": fmt.Sprintf("%d", offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Agents) > 0 {
+		return res.Agents, nil
+	}
+
+	var data struct {
+		Agents []Agent `json:"agents"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && len(data.Agents) > 0 {
+		return data.Agents, nil
+	}
+
+	return []Agent{}, nil
+}
+
+func (c *Client) Subscribe(ctx context.Context, submolt string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/submolts/%s/subscribe", submolt), nil, nil)
 	return err
 }
 
-func (c *Client) Unsubscribe(submolt string) error {
-	_, err := c.request("DELETE", fmt.Sprintf("/submolts/%s/subscribe", submolt), nil, nil)
+func (c *Client) Unsubscribe(ctx context.Context, submolt string) error {
+	_, err := c.request(ctx, "DELETE", fmt.Sprintf("/submolts/%s/subscribe", submolt), nil, nil)
 	return err
 }
 
-func (c *Client) UpdateProfile(description string) error {
-	_, err := c.request("PATCH", "/agents/me", map[string]string{
+func (c *Client) UpdateProfile(ctx context.Context, description string) error {
+	_, err := c.request(ctx, "PATCH", "/agents/me", map[string]string{
 		"description": description,
 	}, nil)
 	return err
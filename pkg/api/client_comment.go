@@ -1,10 +1,105 @@
 package api
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
 
-func (c *Client) CreateComment(postID, content string) error {
-	_, err := c.request("POST", fmt.Sprintf("/posts/%s/comments", postID), map[string]string{
+// CreateComment comments on postID. The returned comment is nil when the
+// server doesn't echo it back.
+func (c *Client) CreateComment(ctx context.Context, postID, content string) (*Comment, error) {
+	res, err := c.request(ctx, "POST", fmt.Sprintf("/posts/%s/comments", postID), map[string]string{
 		"content": content,
 	}, nil)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return commentFrom(res), nil
+}
+
+// CreateReply posts content as a reply to the comment parentID on postID.
+func (c *Client) CreateReply(ctx context.Context, postID, parentID, content string) (*Comment, error) {
+	res, err := c.request(ctx, "POST", fmt.Sprintf("/posts/%s/comments", postID), map[string]string{
+		"content":   content,
+		"parent_id": parentID,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return commentFrom(res), nil
+}
+
+// commentFrom finds the comment in a response, at the root or under data.
+func commentFrom(res *FlexibleResponse) *Comment {
+	if res.Comment != nil {
+		return res.Comment
+	}
+
+	var data struct {
+		Comment Comment `json:"comment"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && data.Comment.ID != "" {
+		return &data.Comment
+	}
+	return nil
+}
+
+// BuildCommentTree nests comments under their ParentID, keeping the original
+// order among siblings. The input may be flat, already nested by the server,
+// or a mix of both (e.g. after appending pages). Comments whose parent isn't
+// present become roots, and duplicate IDs are dropped.
+func BuildCommentTree(comments []Comment) []Comment {
+	var flat []Comment
+	seen := make(map[string]bool)
+	var collect func(cs []Comment, parent string)
+	collect = func(cs []Comment, parent string) {
+		for _, cm := range cs {
+			if cm.ParentID == "" {
+				cm.ParentID = parent
+			}
+			replies := cm.Replies
+			cm.Replies = nil
+			if !seen[cm.ID] {
+				seen[cm.ID] = true
+				flat = append(flat, cm)
+			}
+			collect(replies, cm.ID)
+		}
+	}
+	collect(comments, "")
+
+	children := make(map[string][]Comment)
+	for _, cm := range flat {
+		if cm.ParentID != "" && cm.ParentID != cm.ID && seen[cm.ParentID] {
+			children[cm.ParentID] = append(children[cm.ParentID], cm)
+		}
+	}
+
+	placed := make(map[string]bool)
+	var attach func(cm Comment) Comment
+	attach = func(cm Comment) Comment {
+		placed[cm.ID] = true
+		for _, child := range children[cm.ID] {
+			if !placed[child.ID] {
+				cm.Replies = append(cm.Replies, attach(child))
+			}
+		}
+		return cm
+	}
+
+	var roots []Comment
+	for _, cm := range flat {
+		isRoot := cm.ParentID == "" || cm.ParentID == cm.ID || !seen[cm.ParentID]
+		if isRoot && !placed[cm.ID] {
+			roots = append(roots, attach(cm))
+		}
+	}
+	// Anything left is part of a parent cycle; surface it rather than lose it
+	for _, cm := range flat {
+		if !placed[cm.ID] {
+			roots = append(roots, attach(cm))
+		}
+	}
+	return roots
 }
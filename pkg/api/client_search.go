@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Search types accepted by SearchQuery.Type.
+const (
+	SearchTypePosts    = "posts"
+	SearchTypeComments = "comments"
+	SearchTypeAgents   = "agents"
+	SearchTypeAll      = "all"
+)
+
+// SearchKind says which field of a SearchResult is set.
+type SearchKind string
+
+const (
+	SearchKindPost    SearchKind = "post"
+	SearchKindComment SearchKind = "comment"
+	SearchKindAgent   SearchKind = "agent"
+)
+
+// SearchResult is one hit from /search. Exactly one of Post, Comment and
+// Agent is set, according to Kind.
+type SearchResult struct {
+	Kind       SearchKind
+	Similarity float64 // 0..1, higher is closer
+
+	Post    *Post
+	Comment *Comment
+	Agent   *Agent
+
+	// PostID is the post a comment hit belongs to
+	PostID string
+}
+
+// Key identifies the hit across pages; IDs of different kinds may collide.
+func (r SearchResult) Key() string {
+	switch r.Kind {
+	case SearchKindComment:
+		return "comment:" + r.Comment.ID
+	case SearchKindAgent:
+		return "agent:" + r.Agent.Name
+	default:
+		return "post:" + r.Post.ID
+	}
+}
+
+// UnmarshalJSON decodes the flat wire format, where the "type" field
+// selects the kind and the remaining fields belong to the hit itself.
+func (r *SearchResult) UnmarshalJSON(b []byte) error {
+	var head struct {
+		Type       string  `json:"type"`
+		Similarity float64 `json:"similarity"`
+		PostID     string  `json:"post_id"`
+		Post       *struct {
+			ID string `json:"id"`
+		} `json:"post"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return err
+	}
+
+	*r = SearchResult{Similarity: head.Similarity}
+	switch SearchKind(head.Type) {
+	case SearchKindComment:
+		r.Kind = SearchKindComment
+		r.Comment = new(Comment)
+		r.PostID = head.PostID
+		if r.PostID == "" && head.Post != nil {
+			r.PostID = head.Post.ID
+		}
+		return json.Unmarshal(b, r.Comment)
+	case SearchKindAgent:
+		r.Kind = SearchKindAgent
+		r.Agent = new(Agent)
+		return json.Unmarshal(b, r.Agent)
+	default:
+		// Older servers send bare posts, whose type is "text" or "link"
+		r.Kind = SearchKindPost
+		r.Post = new(Post)
+		return json.Unmarshal(b, r.Post)
+	}
+}
+
+// MarshalJSON is the inverse of UnmarshalJSON.
+func (r SearchResult) MarshalJSON() ([]byte, error) {
+	var hit any
+	switch r.Kind {
+	case SearchKindComment:
+		hit = r.Comment
+	case SearchKindAgent:
+		hit = r.Agent
+	default:
+		hit = r.Post
+	}
+	raw, err := json.Marshal(hit)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	fields["type"] = r.Kind
+	fields["similarity"] = r.Similarity
+	if r.PostID != "" {
+		fields["post_id"] = r.PostID
+	}
+	return json.Marshal(fields)
+}
+
+// Search runs one page of a semantic search. Zero fields in q are left to
+// the server's defaults.
+func (c *Client) Search(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	params := map[string]string{"q": q.Query}
+	if q.Type != "" {
+		params["type"] = q.Type
+	}
+	if q.Submolt != "" {
+		params["submolt"] = q.Submolt
+	}
+	if q.Author != "" {
+		params["author"] = q.Author
+	}
+	if q.Limit > 0 {
+		params["limit"] = fmt.Sprintf("%d", q.Limit)
+		params["offset"] = fmt.Sprintf("%d", q.Offset)
+	}
+	res, err := c.request(ctx, "GET", "/search", nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Results) > 0 {
+		return res.Results, nil
+	}
+
+	var data struct {
+		Results []SearchResult `json:"results"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && len(data.Results) > 0 {
+		return data.Results, nil
+	}
+
+	return []SearchResult{}, nil
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Submolt is a community posts are filed under, shown as m/<name>.
+type Submolt struct {
+	Name            string    `json:"name"`
+	DisplayName     string    `json:"display_name"`
+	Description     string    `json:"description"`
+	SubscriberCount int       `json:"subscriber_count"`
+	IsSubscribed    bool      `json:"is_subscribed"`
+	Owner           string    `json:"owner,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Title is the display name, falling back to m/<name>.
+func (s Submolt) Title() string {
+	if s.DisplayName != "" {
+		return s.DisplayName
+	}
+	return "m/" + s.Name
+}
+
+// Submolt list orders accepted by ListSubmolts.
+const (
+	SubmoltSortPopular = "popular"
+	SubmoltSortNew     = "new"
+	SubmoltSortName    = "name"
+)
+
+func (c *Client) ListSubmolts(ctx context.Context, sort string, limit, offset int) ([]Submolt, error) {
+	params := map[string]string{
+		"limit":  fmt.Sprintf("%d", limit),
+		"offset": fmt.Sprintf("%d", offset),
+	}
+	if sort != "" {
+		params["sort"] = sort
+	}
+	res, err := c.request(ctx, "GET", "/submolts", nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Submolts) > 0 {
+		return res.Submolts, nil
+	}
+
+	var data struct {
+		Submolts []Submolt `json:"submolts"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && len(data.Submolts) > 0 {
+		return data.Submolts, nil
+	}
+
+	return []Submolt{}, nil
+}
+
+func (c *Client) GetSubmolt(ctx context.Context, name string) (*Submolt, error) {
+	res, err := c.request(ctx, "GET", fmt.Sprintf("/submolts/%s", name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return submoltFrom(res)
+}
+
+// CreateSubmolt creates a community owned by the calling agent. name is the
+// URL slug; displayName and description may be empty.
+func (c *Client) CreateSubmolt(ctx context.Context, name, displayName, description string) (*Submolt, error) {
+	res, err := c.request(ctx, "POST", "/submolts", map[string]string{
+		"name":         name,
+		"display_name": displayName,
+		"description":  description,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return submoltFrom(res)
+}
+
+func submoltFrom(res *FlexibleResponse) (*Submolt, error) {
+	if res.Submolt != nil {
+		return res.Submolt, nil
+	}
+
+	var data struct {
+		Submolt Submolt `json:"submolt"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err == nil && data.Submolt.Name != "" {
+		return &data.Submolt, nil
+	}
+
+	return nil, fmt.Errorf("could not find submolt in response")
+}
@@ -0,0 +1,287 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/moltbooktest"
+)
+
+var shapes = []struct {
+	name  string
+	shape moltbooktest.Shape
+}{
+	{"unwrapped", moltbooktest.ShapeUnwrapped},
+	{"wrapped", moltbooktest.ShapeWrapped},
+}
+
+func newServer(t *testing.T, opts ...moltbooktest.Option) *moltbooktest.Server {
+	t.Helper()
+	srv := moltbooktest.NewServer(append([]moltbooktest.Option{moltbooktest.WithSeed()}, opts...)...)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fastRetries keeps retry tests from sleeping through the default backoff.
+var fastRetries = api.WithRetryPolicy(api.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range shapes {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(t, moltbooktest.WithShape(tc.shape))
+			c := srv.Client(moltbooktest.SeedAPIKey)
+			ctx := context.Background()
+
+			me, err := c.GetMe(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if me.Name != "molty" {
+				t.Errorf("GetMe name = %q, want molty", me.Name)
+			}
+
+			posts, err := c.GetFeed(ctx, "new", 25, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(posts) != len(srv.Posts()) {
+				t.Errorf("GetFeed returned %d posts, want %d", len(posts), len(srv.Posts()))
+			}
+
+			created, err := c.CreatePost(ctx, "general", "Round trip", "Sent through the fake.")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.GetPost(ctx, created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != "Round trip" || got.Content != "Sent through the fake." || got.Author.Name != "molty" {
+				t.Errorf("GetPost = %+v", got)
+			}
+
+			comment, err := c.CreateComment(ctx, created.ID, "First!")
+			if err != nil {
+				t.Fatal(err)
+			}
+			comments, err := c.GetComments(ctx, created.ID, api.CommentQuery{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != 1 || comments[0].ID != comment.ID || comments[0].Content != "First!" {
+				t.Errorf("GetComments = %+v", comments)
+			}
+
+			if _, err := c.GetPost(ctx, "post_missing"); !errors.Is(err, api.ErrNotFound) {
+				t.Errorf("GetPost of a missing post: err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestWithHTTPClientKeepsCallerTimeout(t *testing.T) {
+	srv := newServer(t)
+	for _, opts := range [][]api.Option{nil, {api.WithTimeout(time.Second)}} {
+		hc := &http.Client{Timeout: 42 * time.Second}
+		c := srv.Client(moltbooktest.SeedAPIKey, append(opts, api.WithHTTPClient(hc))...)
+		if _, err := c.GetMe(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if hc.Timeout != 42*time.Second {
+			t.Errorf("caller's Timeout changed to %v", hc.Timeout)
+		}
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	srv := newServer(t)
+	_, err := srv.Client("moltbook_wrong_key").GetMe(context.Background())
+	if !errors.Is(err, api.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestReadWaitsOutRetryAfter(t *testing.T) {
+	srv := newServer(t)
+	srv.RateLimitNext("/posts", 1, time.Second)
+	c := srv.Client(moltbooktest.SeedAPIKey)
+
+	var attempts int
+	ctx := api.WithCallOptions(context.Background(), api.CallOptions{Attempts: &attempts})
+	start := time.Now()
+	posts, err := c.GetFeed(ctx, "new", 25, 0)
+	if err != nil {
+		t.Fatalf("GetFeed should wait out a short Retry-After: %v", err)
+	}
+	if len(posts) == 0 {
+		t.Error("GetFeed returned no posts")
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %v, want about a second", elapsed)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestReadFailsOnLongRetryAfter(t *testing.T) {
+	srv := newServer(t)
+	srv.RateLimitNext("/posts", 1, 5*time.Minute)
+	c := srv.Client(moltbooktest.SeedAPIKey)
+
+	_, err := c.GetFeed(context.Background(), "new", 25, 0)
+	var rl *api.RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("err = %v, want a RateLimitError", err)
+	}
+	if rl.RetryAfter != 5*time.Minute {
+		t.Errorf("RetryAfter = %v, want 5m", rl.RetryAfter)
+	}
+}
+
+func TestWriteRateLimitStartsCooldown(t *testing.T) {
+	srv := newServer(t)
+	srv.RateLimitNext("/posts/", 1, 90*time.Second)
+	c := srv.Client(moltbooktest.SeedAPIKey)
+	ctx := context.Background()
+	postID := srv.Posts()[0].ID
+	before := len(srv.Comments(postID))
+
+	_, err := c.CreateComment(ctx, postID, "Too soon")
+	var rl *api.RateLimitError
+	if !errors.As(err, &rl) || rl.RetryAfter != 90*time.Second {
+		t.Fatalf("err = %v, want a 90s RateLimitError", err)
+	}
+	status := classStatus(t, c, api.ClassComments)
+	if until := time.Until(status.CooldownUntil); until < 80*time.Second || until > 90*time.Second {
+		t.Errorf("cooldown ends in %v, want about 90s", until)
+	}
+
+	// The cooldown is enforced client-side, without asking the server
+	_, err = c.CreateComment(ctx, postID, "Still too soon")
+	if !errors.As(err, &rl) || !strings.Contains(rl.Message, "client-side") {
+		t.Errorf("err = %v, want a client-side RateLimitError", err)
+	}
+	if n := len(srv.Comments(postID)); n != before {
+		t.Errorf("%d comments were created while rate limited", n-before)
+	}
+}
+
+func TestRejectedWriteRefundsToken(t *testing.T) {
+	srv := newServer(t)
+	c := srv.Client(moltbooktest.SeedAPIKey)
+	ctx := context.Background()
+
+	if _, err := c.CreatePost(ctx, "no-such-submolt", "Lost", "Nowhere to go."); !errors.Is(err, api.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if _, err := c.CreatePost(ctx, "general", "Found", "The failed post didn't use up the budget."); err != nil {
+		t.Errorf("post after a rejected one: %v", err)
+	}
+}
+
+func TestFailedWriteKeepsToken(t *testing.T) {
+	srv := newServer(t)
+	srv.Inject(moltbooktest.Failure{Method: http.MethodPost, Path: "/posts", Drop: true})
+	c := srv.Client(moltbooktest.SeedAPIKey)
+	ctx := context.Background()
+
+	// The post may have been created before the connection dropped, so
+	// it still counts against the limit
+	var netErr *api.NetworkError
+	if _, err := c.CreatePost(ctx, "general", "Dropped", "Did it arrive?"); !errors.As(err, &netErr) {
+		t.Fatalf("err = %v, want a NetworkError", err)
+	}
+	var rl *api.RateLimitError
+	if _, err := c.CreatePost(ctx, "general", "Again", "Second try."); !errors.As(err, &rl) {
+		t.Errorf("err = %v, want a client-side RateLimitError", err)
+	}
+	if s := classStatus(t, c, api.ClassPosts); s.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", s.Remaining)
+	}
+}
+
+func TestWriteRetriesOnlyWithIdempotencyKey(t *testing.T) {
+	srv := newServer(t)
+	c := srv.Client(moltbooktest.SeedAPIKey, fastRetries)
+	postID := srv.Posts()[0].ID
+	before := len(srv.Comments(postID))
+
+	srv.Inject(moltbooktest.Failure{Method: http.MethodPost, Path: "/posts/", Status: http.StatusBadGateway})
+	var attempts int
+	ctx := api.WithCallOptions(context.Background(), api.CallOptions{Attempts: &attempts})
+	if _, err := c.CreateComment(ctx, postID, "No key"); err == nil {
+		t.Fatal("want the 502 without an idempotency key")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts without a key = %d, want 1", attempts)
+	}
+
+	// A fresh client, so the budget used above doesn't matter
+	c = srv.Client(moltbooktest.SeedAPIKey, fastRetries)
+	srv.Inject(moltbooktest.Failure{Method: http.MethodPost, Path: "/posts/", Status: http.StatusBadGateway})
+	attempts = 0
+	ctx = api.WithCallOptions(context.Background(), api.CallOptions{Attempts: &attempts, IdempotencyKey: api.NewIdempotencyKey()})
+	if _, err := c.CreateComment(ctx, postID, "With key"); err != nil {
+		t.Fatalf("with a key the 502 should be retried: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts with a key = %d, want 2", attempts)
+	}
+	if n := len(srv.Comments(postID)); n != before+1 {
+		t.Errorf("created %d comments, want 1", n-before)
+	}
+}
+
+func TestRetriedWriteIsNotDuplicated(t *testing.T) {
+	srv := newServer(t)
+	c := srv.Client(moltbooktest.SeedAPIKey, fastRetries)
+	postID := srv.Posts()[0].ID
+	before := len(srv.Comments(postID))
+
+	// The comment is created, but the response is lost
+	srv.Inject(moltbooktest.Failure{Method: http.MethodPost, Path: "/posts/", Status: http.StatusBadGateway, AfterHandling: true})
+	var attempts int
+	ctx := api.WithCallOptions(context.Background(), api.CallOptions{Attempts: &attempts, IdempotencyKey: api.NewIdempotencyKey()})
+	comment, err := c.CreateComment(ctx, postID, "Only once")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+	comments := srv.Comments(postID)
+	if len(comments) != before+1 {
+		t.Fatalf("created %d comments, want 1", len(comments)-before)
+	}
+	if last := comments[len(comments)-1]; comment.ID != last.ID {
+		t.Errorf("retry returned comment %s, want the one created first, %s", comment.ID, last.ID)
+	}
+
+	// A new key is a new comment
+	ctx = api.WithCallOptions(context.Background(), api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
+	srv.Inject(moltbooktest.Failure{Method: http.MethodPost, Path: "/posts/", Status: http.StatusBadGateway, AfterHandling: true})
+	c = srv.Client(moltbooktest.SeedAPIKey, fastRetries)
+	if _, err := c.CreateComment(ctx, postID, "Only once"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Comments(postID)); n != before+2 {
+		t.Errorf("created %d comments, want 2", n-before)
+	}
+}
+
+func classStatus(t *testing.T, c *api.Client, class api.EndpointClass) api.RateLimitStatus {
+	t.Helper()
+	for _, s := range c.RateLimitStatus() {
+		if s.Class == class {
+			return s
+		}
+	}
+	t.Fatalf("no status for %s", class)
+	return api.RateLimitStatus{}
+}
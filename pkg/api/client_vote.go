@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Vote is the caller's vote on a post or comment.
+type Vote int
+
+const (
+	VoteNone Vote = 0
+	VoteUp   Vote = 1
+	VoteDown Vote = -1
+)
+
+// Apply moves a vote from old to v, adjusting the up/down counters the way
+// the server does, so callers can update their copy without a re-fetch.
+func (v Vote) Apply(old Vote, upvotes, downvotes *int) {
+	switch old {
+	case VoteUp:
+		*upvotes--
+	case VoteDown:
+		*downvotes--
+	}
+	switch v {
+	case VoteUp:
+		*upvotes++
+	case VoteDown:
+		*downvotes++
+	}
+}
+
+func (c *Client) UpvotePost(ctx context.Context, postID string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/posts/%s/upvote", postID), map[string]string{}, nil)
+	return err
+}
+
+func (c *Client) DownvotePost(ctx context.Context, postID string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/posts/%s/downvote", postID), map[string]string{}, nil)
+	return err
+}
+
+// ClearPostVote removes the caller's up- or downvote from a post.
+func (c *Client) ClearPostVote(ctx context.Context, postID string) error {
+	_, err := c.request(ctx, "DELETE", fmt.Sprintf("/posts/%s/vote", postID), nil, nil)
+	return err
+}
+
+func (c *Client) UpvoteComment(ctx context.Context, commentID string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/comments/%s/upvote", commentID), map[string]string{}, nil)
+	return err
+}
+
+func (c *Client) DownvoteComment(ctx context.Context, commentID string) error {
+	_, err := c.request(ctx, "POST", fmt.Sprintf("/comments/%s/downvote", commentID), map[string]string{}, nil)
+	return err
+}
+
+// ClearCommentVote removes the caller's up- or downvote from a comment.
+func (c *Client) ClearCommentVote(ctx context.Context, commentID string) error {
+	_, err := c.request(ctx, "DELETE", fmt.Sprintf("/comments/%s/vote", commentID), nil, nil)
+	return err
+}
+
+// VotePost casts v on a post, clearing any existing vote for VoteNone.
+func (c *Client) VotePost(ctx context.Context, postID string, v Vote) error {
+	switch v {
+	case VoteUp:
+		return c.UpvotePost(ctx, postID)
+	case VoteDown:
+		return c.DownvotePost(ctx, postID)
+	default:
+		return c.ClearPostVote(ctx, postID)
+	}
+}
+
+// VoteComment casts v on a comment, clearing any existing vote for VoteNone.
+func (c *Client) VoteComment(ctx context.Context, commentID string, v Vote) error {
+	switch v {
+	case VoteUp:
+		return c.UpvoteComment(ctx, commentID)
+	case VoteDown:
+		return c.DownvoteComment(ctx, commentID)
+	default:
+		return c.ClearCommentVote(ctx, commentID)
+	}
+}
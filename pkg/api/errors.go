@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matched by APIError.Is, so callers can use errors.Is
+// without caring about the exact status or message.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// APIError is returned when the server answers with a failure.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Hint       string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed: %d", e.StatusCode)
+	}
+	if e.Hint != "" {
+		return fmt.Sprintf("%s (Hint: %s)", e.Message, e.Hint)
+	}
+	return e.Message
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// rejected reports whether the server refused the request outright, i.e.
+// answered 4xx other than 429. Network errors, timeouts and 5xx don't
+// count: the write may have gone through before the failure.
+func rejected(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 &&
+		apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// RateLimitError is returned on HTTP 429. RetryAt is RetryAfter resolved
+// against the time the response was received.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	RetryAt    time.Time
+	Message    string
+	Hint       string
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "Rate limit exceeded"
+	if e.Hint != "" {
+		msg += ": " + e.Hint
+	} else if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return fmt.Sprintf("%s (Retry after %d seconds)", msg, int(e.RetryAfter.Seconds()))
+}
+
+// NetworkError wraps failures where no response was received at all.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("network error: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
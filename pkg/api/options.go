@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client in NewClient.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	baseURL    string
+	timeout    time.Duration
+	httpClient *http.Client
+	retry      RetryPolicy
+	userAgent  string
+}
+
+// WithBaseURL points the client at another server, e.g. staging or a local fake.
+func WithBaseURL(url string) Option {
+	return func(o *clientOptions) {
+		if url != "" {
+			o.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithTimeout sets the per-attempt request timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHTTPClient makes the client send requests through hc, for custom
+// transports or proxies. Its Timeout is kept unless WithTimeout is also given.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = hc
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for every call on the client.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retry = p
+	}
+}
+
+// WithUserAgent overrides the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(o *clientOptions) {
+		if ua != "" {
+			o.userAgent = ua
+		}
+	}
+}
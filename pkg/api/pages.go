@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"iter"
+)
+
+const defaultPageSize = 20
+
+// FeedQuery selects which feed FeedPages walks. Submolt takes precedence
+// over Personalized; with neither set the global feed is used.
+type FeedQuery struct {
+	Sort         string // hot, new, top, rising; defaults to hot
+	Submolt      string
+	Personalized bool
+	PageSize     int
+}
+
+// SearchQuery selects what Search returns and SearchPages walks.
+type SearchQuery struct {
+	Query   string
+	Type    string // posts, comments, agents, all; defaults to posts
+	Submolt string // Only hits in this submolt
+	Author  string // Only hits by this agent
+
+	// Limit and Offset page a single Search call; SearchPages ignores them
+	Limit  int
+	Offset int
+
+	PageSize int
+}
+
+// paginate walks an offset-paged endpoint, yielding each item once. Items
+// that reappear on a later page (the hot ranking shifts while you scroll)
+// are skipped. It stops on a short page, an error, or a page with nothing
+// new, which also guards against endpoints that ignore offset.
+func paginate[T any](ctx context.Context, pageSize int, id func(T) string, fetch func(ctx context.Context, limit, offset int) ([]T, error)) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return func(yield func(T, error) bool) {
+		seen := make(map[string]bool)
+		offset := 0
+		for {
+			items, err := fetch(ctx, pageSize, offset)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			fresh := 0
+			for _, item := range items {
+				key := id(item)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				fresh++
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < pageSize || fresh == 0 {
+				return
+			}
+			offset += len(items)
+		}
+	}
+}
+
+// FeedPages iterates over a feed, fetching pages as needed:
+//
+//	for post, err := range client.FeedPages(ctx, api.FeedQuery{Sort: "new"}) {
+//		if err != nil { ... }
+//	}
+func (c *Client) FeedPages(ctx context.Context, q FeedQuery) iter.Seq2[Post, error] {
+	sort := q.Sort
+	if sort == "" {
+		sort = "hot"
+	}
+	return paginate(ctx, q.PageSize, postKey, func(ctx context.Context, limit, offset int) ([]Post, error) {
+		switch {
+		case q.Submolt != "":
+			return c.GetSubmoltFeed(ctx, q.Submolt, sort, limit, offset)
+		case q.Personalized:
+			return c.GetPersonalizedFeed(ctx, sort, limit, offset)
+		default:
+			return c.GetFeed(ctx, sort, limit, offset)
+		}
+	})
+}
+
+// SearchPages iterates over search results, most similar first.
+func (c *Client) SearchPages(ctx context.Context, q SearchQuery) iter.Seq2[SearchResult, error] {
+	if q.Type == "" {
+		q.Type = SearchTypePosts
+	}
+	return paginate(ctx, q.PageSize, SearchResult.Key, func(ctx context.Context, limit, offset int) ([]SearchResult, error) {
+		q.Limit, q.Offset = limit, offset
+		return c.Search(ctx, q)
+	})
+}
+
+// CommentPages iterates over the comments on a post. q.Limit is the page
+// size; q.Offset is ignored since iteration always starts at the top.
+func (c *Client) CommentPages(ctx context.Context, postID string, q CommentQuery) iter.Seq2[Comment, error] {
+	return paginate(ctx, q.Limit, commentKey, func(ctx context.Context, limit, offset int) ([]Comment, error) {
+		return c.GetComments(ctx, postID, CommentQuery{Sort: q.Sort, Limit: limit, Offset: offset})
+	})
+}
+
+// FollowerPages iterates over the agents following name.
+func (c *Client) FollowerPages(ctx context.Context, name string, pageSize int) iter.Seq2[Agent, error] {
+	return paginate(ctx, pageSize, agentKey, func(ctx context.Context, limit, offset int) ([]Agent, error) {
+		return c.ListFollowers(ctx, name, limit, offset)
+	})
+}
+
+// FollowingPages iterates over the agents name follows.
+func (c *Client) FollowingPages(ctx context.Context, name string, pageSize int) iter.Seq2[Agent, error] {
+	return paginate(ctx, pageSize, agentKey, func(ctx context.Context, limit, offset int) ([]Agent, error) {
+		return c.ListFollowing(ctx, name, limit, offset)
+	})
+}
+
+func postKey(p Post) string        { return p.ID }
+func commentKey(cm Comment) string { return cm.ID }
+func agentKey(a Agent) string      { return a.Name }
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups endpoints that share a server-side rate limit.
+type EndpointClass string
+
+const (
+	// ClassReads is the general request budget. Everything that isn't
+	// creating a post or a comment (votes, follows, etc.) counts against it.
+	ClassReads    EndpointClass = "reads"
+	ClassPosts    EndpointClass = "posts"
+	ClassComments EndpointClass = "comments"
+)
+
+// Mirrors the limits Moltbook documents for agents.
+var defaultBuckets = map[EndpointClass]struct {
+	capacity int
+	interval time.Duration // time to regain one token
+}{
+	ClassReads:    {capacity: 100, interval: time.Minute / 100},
+	ClassPosts:    {capacity: 1, interval: 30 * time.Minute},
+	ClassComments: {capacity: 1, interval: 20 * time.Second},
+}
+
+// maxAutoWait caps how long an idempotent request will block waiting for
+// budget. Longer waits are surfaced as a RateLimitError instead.
+const maxAutoWait = time.Minute
+
+// RateLimitStatus is a snapshot of one endpoint class budget.
+type RateLimitStatus struct {
+	Class         EndpointClass
+	Remaining     int
+	Capacity      int
+	CooldownUntil time.Time // When the next request is allowed; zero if one is available now
+}
+
+type tokenBucket struct {
+	capacity      float64
+	tokens        float64
+	interval      time.Duration
+	last          time.Time
+	cooldownUntil time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.capacity, b.tokens+float64(elapsed)/float64(b.interval))
+	}
+	b.last = now
+}
+
+// reserve takes a token and returns 0, or returns how long until one is
+// available without taking anything.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if now.Before(b.cooldownUntil) {
+		return b.cooldownUntil.Sub(now)
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[EndpointClass]*tokenBucket
+}
+
+func newRateLimiter() *rateLimiter {
+	now := time.Now()
+	l := &rateLimiter{buckets: make(map[EndpointClass]*tokenBucket)}
+	for class, cfg := range defaultBuckets {
+		l.buckets[class] = &tokenBucket{
+			capacity: float64(cfg.capacity),
+			tokens:   float64(cfg.capacity),
+			interval: cfg.interval,
+			last:     now,
+		}
+	}
+	return l
+}
+
+// wait takes a token for class. If block is false, or the wait would be
+// longer than maxAutoWait, it fails fast with a RateLimitError.
+func (l *rateLimiter) wait(ctx context.Context, class EndpointClass, block bool) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		d := l.buckets[class].reserve(now)
+		l.mu.Unlock()
+		if d == 0 {
+			return nil
+		}
+		if !block || d > maxAutoWait {
+			return &RateLimitError{
+				RetryAfter: d,
+				RetryAt:    now.Add(d),
+				Message:    fmt.Sprintf("client-side %s limit reached", class),
+			}
+		}
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// cooldown blocks class until the server-provided retry time has passed.
+func (l *rateLimiter) cooldown(class EndpointClass, d time.Duration) {
+	if d <= 0 {
+		d = time.Second
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[class]
+	if until := time.Now().Add(d); until.After(b.cooldownUntil) {
+		b.cooldownUntil = until
+	}
+	b.tokens = 0
+}
+
+// refund gives back the token of a request the server rejected for reasons
+// other than rate limiting, so a failed post doesn't lock posting for 30m.
+func (l *rateLimiter) refund(class EndpointClass) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[class]
+	b.tokens = min(b.capacity, b.tokens+1)
+}
+
+func (l *rateLimiter) status() []RateLimitStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	var out []RateLimitStatus
+	for _, class := range []EndpointClass{ClassReads, ClassPosts, ClassComments} {
+		b := l.buckets[class]
+		b.refill(now)
+		s := RateLimitStatus{
+			Class:     class,
+			Remaining: int(b.tokens),
+			Capacity:  int(b.capacity),
+		}
+		if now.Before(b.cooldownUntil) {
+			s.CooldownUntil = b.cooldownUntil
+			s.Remaining = 0
+		} else if b.tokens < 1 {
+			s.CooldownUntil = now.Add(time.Duration((1 - b.tokens) * float64(b.interval)))
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func classifyEndpoint(method, path string) EndpointClass {
+	if method == "POST" {
+		if path == "/posts" {
+			return ClassPosts
+		}
+		if strings.HasSuffix(path, "/comments") {
+			return ClassComments
+		}
+	}
+	return ClassReads
+}
+
+// RateLimitStatus reports the remaining client-side budget and any active
+// server cooldown for each endpoint class.
+func (c *Client) RateLimitStatus() []RateLimitStatus {
+	return c.limiter.status()
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Only network errors
+// and 5xx responses are retried; rate limits are handled by the limiter.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; <= 1 disables retries
+	BaseDelay   time.Duration // Backoff before the second attempt, doubled each time
+	MaxDelay    time.Duration // Upper bound for a single backoff; <= 0 means no bound
+}
+
+// DefaultRetryPolicy keeps the attempt count and delays the client always used.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   1 * time.Second,
+	MaxDelay:    5 * time.Second,
+}
+
+// backoff returns a "full jitter" delay for the given (1-based) attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d>>(attempt-1) != p.BaseDelay {
+		d = math.MaxInt64 // Overflowed
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+// CallOptions tweak a single request. Attach them with WithCallOptions.
+type CallOptions struct {
+	// Retry overrides the client's RetryPolicy for this call.
+	Retry *RetryPolicy
+	// IdempotencyKey is sent as the Idempotency-Key header. POST, PATCH
+	// and DELETE are only retried when one is set, so a timed-out write
+	// can't create a duplicate.
+	IdempotencyKey string
+	// Attempts, if non-nil, is incremented for every HTTP attempt made.
+	Attempts *int
+}
+
+type callOptionsKey struct{}
+
+func WithCallOptions(ctx context.Context, opts CallOptions) context.Context {
+	return context.WithValue(ctx, callOptionsKey{}, opts)
+}
+
+func callOptionsFrom(ctx context.Context) CallOptions {
+	opts, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+	return opts
+}
+
+// NewIdempotencyKey returns a random key suitable for CallOptions.IdempotencyKey.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		// Not expected to happen, but a shared or empty key would merge
+		// unrelated writes, so fall back to the non-crypto generator
+		binary.LittleEndian.PutUint64(b, rand.Uint64())
+		binary.LittleEndian.PutUint64(b[8:], rand.Uint64())
+	}
+	return hex.EncodeToString(b)
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+func (c *Client) doWithRetry(ctx context.Context, method, path string, body interface{}, params map[string]string, opts CallOptions) (*FlexibleResponse, error) {
+	policy := c.retry
+	if opts.Retry != nil {
+		policy = *opts.Retry
+	}
+	canRetry := method == "GET" || opts.IdempotencyKey != ""
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.do(ctx, method, path, body, params, opts.IdempotencyKey)
+		if opts.Attempts != nil {
+			*opts.Attempts++
+		}
+		if err == nil || !canRetry || attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return res, err
+		}
+		select {
+		case <-time.After(policy.backoff(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		max     time.Duration // Backoffs are drawn from [0, max)
+	}{
+		{"first", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 1, time.Second},
+		{"doubled", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 3, 4 * time.Second},
+		{"capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 4, 5 * time.Second},
+		{"no cap", RetryPolicy{BaseDelay: time.Second}, 4, 8 * time.Second},
+		{"no cap overflow", RetryPolicy{BaseDelay: time.Second}, 64, time.Duration(1<<63 - 1)},
+		{"no delay", RetryPolicy{}, 3, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var longest time.Duration
+			for range 200 {
+				d := tc.policy.backoff(tc.attempt)
+				if d < 0 || d > tc.max || tc.max > 0 && d == tc.max {
+					t.Fatalf("backoff = %v, want in [0, %v)", d, tc.max)
+				}
+				longest = max(longest, d)
+			}
+			// Full jitter spreads over the whole range
+			if longest < tc.max/2 {
+				t.Errorf("longest of 200 backoffs = %v, want near %v", longest, tc.max)
+			}
+		})
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		key := NewIdempotencyKey()
+		if len(key) != 32 || seen[key] {
+			t.Fatalf("key %q is malformed or repeated", key)
+		}
+		seen[key] = true
+	}
+}
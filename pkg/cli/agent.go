@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/starkbaknet/moltbook-client/pkg/agent"
+)
+
+func init() {
+	register(&command{name: "agent", args: "run [--config agent.yaml] [--dry-run] [--once] [--verbose]", summary: "Run an autonomous agent on a heartbeat", run: runAgent})
+}
+
+func runAgent(e *env, args []string) error {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		args = []string{"run", args[0]}
+	}
+	if len(args) == 0 || args[0] != "run" {
+		return usagef("expected a subcommand: run")
+	}
+
+	fs := e.bareFlags("agent")
+	path := fs.String("config", "agent.yaml", "agent configuration file")
+	dryRun := fs.Bool("dry-run", false, "print intended actions instead of carrying them out")
+	once := fs.Bool("once", false, "run a single heartbeat and exit, e.g. from cron")
+	verbose := fs.Bool("verbose", false, "also log items that need no response")
+	rest, err := e.parse(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+
+	cfg, err := agent.LoadConfig(*path)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *dryRun {
+		cfg.DryRun = true
+	}
+
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if *verbose {
+		opts.Level = slog.LevelDebug
+	}
+	var handler slog.Handler = slog.NewTextHandler(e.stderr, opts)
+	if cfg.LogFormat == "json" {
+		handler = slog.NewJSONHandler(e.stderr, opts)
+	}
+
+	logger := slog.New(handler)
+	var responder agent.Responder = agent.NopResponder{}
+	if len(cfg.Responder.Command) > 0 {
+		responder = agent.ExecResponder{Command: cfg.Responder.Command, Timeout: cfg.Responder.Timeout, Stderr: e.stderr}
+	} else {
+		logger.Info("no responder.command configured, only observing")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	runner := agent.NewRunner(c, responder, cfg, logger, e.stdout)
+	if *once {
+		return runner.RunOnce(e.ctx)
+	}
+	return runner.Run(e.ctx)
+}
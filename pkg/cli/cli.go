@@ -0,0 +1,223 @@
+// Package cli implements the headless subcommands, e.g. `moltbook feed`,
+// for scripts, cron jobs and agent runtimes that can't drive the TUI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/config"
+)
+
+// Exit codes, so callers can tell failures apart without parsing stderr.
+const (
+	ExitOK          = 0
+	ExitError       = 1 // Anything not covered below
+	ExitUsage       = 2 // Bad flags or arguments
+	ExitAuth        = 3 // No credentials, or the API key was rejected
+	ExitNotFound    = 4
+	ExitRateLimited = 5
+	ExitNetwork     = 6
+)
+
+// ErrNotLoggedIn is returned when neither MOLTBOOK_API_KEY nor the saved
+// credentials provide an API key.
+var ErrNotLoggedIn = errors.New("not logged in: run moltbook to register, or set MOLTBOOK_API_KEY")
+
+type command struct {
+	name    string
+	args    string // Synopsis shown in usage, e.g. "<post-id> [flags]"
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// IsCommand reports whether name is a subcommand Run handles.
+func IsCommand(name string) bool {
+	switch name {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	_, ok := commands[name]
+	return ok
+}
+
+// env is what a command runs against.
+type env struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code.
+func Run(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	e := &env{ctx: ctx, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	return e.run(args)
+}
+
+func (e *env) run(args []string) int {
+	if len(args) == 0 {
+		e.usage()
+		return ExitUsage
+	}
+	if args[0] == "help" && len(args) > 1 && commands[args[1]] != nil {
+		args = []string{args[1], "-h"} // moltbook help feed
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		e.usage()
+		if IsCommand(args[0]) {
+			return ExitOK // Asked for help
+		}
+		return ExitUsage
+	}
+
+	err := cmd.run(e, args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	fmt.Fprintf(e.stderr, "moltbook %s: %v\n", cmd.name, err)
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		fmt.Fprintf(e.stderr, "usage: moltbook %s %s\n", cmd.name, cmd.args)
+	}
+	return ExitCode(err)
+}
+
+func (e *env) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Usage: moltbook [command] [flags]\n\n")
+	b.WriteString("Without a command the interactive TUI starts.\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-10s %s\n", name, commands[name].summary)
+	}
+	b.WriteString("\nRun 'moltbook <command> -h' for a command's flags.\n")
+	fmt.Fprint(e.stderr, b.String())
+}
+
+// help prints a command's synopsis and flags for -h.
+func (e *env) help(fs *flag.FlagSet) {
+	if cmd := commands[fs.Name()]; cmd != nil {
+		fmt.Fprintf(e.stderr, "usage: moltbook %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+	}
+	fs.SetOutput(e.stderr)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+}
+
+// ExitCode maps an error from a command to the process exit code.
+func ExitCode(err error) int {
+	var (
+		uerr   *usageError
+		rl     *api.RateLimitError
+		netErr *api.NetworkError
+	)
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &uerr):
+		return ExitUsage
+	case errors.Is(err, ErrNotLoggedIn), errors.Is(err, api.ErrUnauthorized):
+		return ExitAuth
+	case errors.Is(err, api.ErrNotFound):
+		return ExitNotFound
+	case errors.As(err, &rl):
+		return ExitRateLimited
+	case errors.As(err, &netErr):
+		return ExitNetwork
+	}
+	return ExitError
+}
+
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef reports bad arguments; run prints the command's synopsis after it.
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// client returns an API client for MOLTBOOK_API_KEY or the saved credentials.
+func (e *env) client() (*api.Client, error) {
+	key := os.Getenv("MOLTBOOK_API_KEY")
+	if key == "" {
+		cfg, err := config.LoadConfig()
+		if err != nil || cfg.APIKey == "" {
+			return nil, ErrNotLoggedIn
+		}
+		key = cfg.APIKey
+	}
+	return api.NewClient(key, api.WithBaseURL(os.Getenv("MOLTBOOK_BASE_URL"))), nil
+}
+
+// flags starts a command's flag set, including the shared --output flag.
+func (e *env) flags(name string, out *format) *flag.FlagSet {
+	fs := e.bareFlags(name)
+	*out = formatTable
+	fs.Var(out, "output", "output format: table, json or jsonl")
+	fs.Var(out, "o", "shorthand for -output")
+	return fs
+}
+
+// bareFlags starts a flag set for commands without --output.
+func (e *env) bareFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	// parse reports errors and prints help itself
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return fs
+}
+
+// parse parses flags that may come before, after or between positional
+// arguments, and returns the positional ones. Everything after "--" is
+// positional.
+func (e *env) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				e.help(fs)
+				return nil, err
+			}
+			return nil, usagef("%v", err)
+		}
+		rest := fs.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
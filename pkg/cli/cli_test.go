@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/moltbooktest"
+)
+
+// newServer points the commands at a seeded fake, logged in as molty.
+func newServer(t *testing.T) *moltbooktest.Server {
+	t.Helper()
+	srv := moltbooktest.NewServer(moltbooktest.WithSeed())
+	t.Cleanup(srv.Close)
+	t.Setenv("MOLTBOOK_BASE_URL", srv.BaseURL())
+	t.Setenv("MOLTBOOK_API_KEY", moltbooktest.SeedAPIKey)
+	return srv
+}
+
+// run runs a command line with stdin and returns the exit code and output.
+func run(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	e := &env{ctx: context.Background(), stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut}
+	code = e.run(args)
+	return code, out.String(), errOut.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		want   []string
+		parent string
+	}{
+		{"flags first", []string{"--parent", "c1", "post_1", "hello"}, []string{"post_1", "hello"}, "c1"},
+		{"flags last", []string{"post_1", "hello", "--parent=c1"}, []string{"post_1", "hello"}, "c1"},
+		{"flags between", []string{"post_1", "-parent", "c1", "hello"}, []string{"post_1", "hello"}, "c1"},
+		{"double dash", []string{"post_1", "--", "--parent", "c1"}, []string{"post_1", "--parent", "c1"}, ""},
+		{"none", nil, nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &env{}
+			var out format
+			fs := e.flags("comment", &out)
+			parent := fs.String("parent", "", "")
+			got, err := e.parse(fs, tc.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.want) || *parent != tc.parent {
+				t.Errorf("positional %q, parent %q; want %q, %q", got, *parent, tc.want, tc.parent)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var stderr bytes.Buffer
+	e := &env{stderr: &stderr}
+	var out format
+	fs := e.flags("feed", &out)
+	fs.Int("limit", 20, "number of posts")
+
+	var uerr *usageError
+	if _, err := e.parse(fs, []string{"--limit", "many"}); !errors.As(err, &uerr) {
+		t.Errorf("bad value: err = %v, want a usage error", err)
+	}
+	if _, err := e.parse(fs, []string{"--bogus"}); !errors.As(err, &uerr) {
+		t.Errorf("unknown flag: err = %v, want a usage error", err)
+	}
+	if _, err := e.parse(fs, []string{"-o", "xml"}); !errors.As(err, &uerr) {
+		t.Errorf("unknown format: err = %v, want a usage error", err)
+	}
+	if _, err := e.parse(fs, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("-h: err = %v, want flag.ErrHelp", err)
+	}
+	if !strings.Contains(stderr.String(), "-limit") {
+		t.Errorf("-h printed %q, want the flags", stderr.String())
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{usagef("missing post ID"), ExitUsage},
+		{ErrNotLoggedIn, ExitAuth},
+		{fmt.Errorf("getting me: %w", api.ErrUnauthorized), ExitAuth},
+		{api.ErrNotFound, ExitNotFound},
+		{&api.RateLimitError{RetryAfter: time.Minute}, ExitRateLimited},
+		{&api.NetworkError{Err: errors.New("connection refused")}, ExitNetwork},
+		{errors.New("something else"), ExitError},
+	}
+	for _, tc := range tests {
+		if got := ExitCode(tc.err); got != tc.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, srv *moltbooktest.Server)
+		stdin string
+		args  []string
+		want  int
+	}{
+		{"no command", nil, "", nil, ExitUsage},
+		{"unknown command", nil, "", []string{"frobnicate"}, ExitUsage},
+		{"help", nil, "", []string{"help"}, ExitOK},
+		{"command help", nil, "", []string{"help", "feed"}, ExitOK},
+		{"ok", nil, "", []string{"whoami"}, ExitOK},
+		{"bad sort", nil, "", []string{"feed", "--sort", "sideways"}, ExitUsage},
+		{"stray argument", nil, "", []string{"whoami", "extra"}, ExitUsage},
+		{"post without body", nil, "", []string{"post", "--title", "Hi"}, ExitUsage},
+		{"post from empty stdin", nil, "", []string{"post", "--title", "Hi", "--content", "-"}, ExitUsage},
+		{"post from blank stdin", nil, " \n\t\n", []string{"post", "--title", "Hi", "--content", "-"}, ExitUsage},
+		{"comment from empty stdin", nil, "", []string{"comment", "post_1", "--content", "-"}, ExitUsage},
+		{"not found", nil, "", []string{"upvote", "post_missing"}, ExitNotFound},
+		{"bad key", func(t *testing.T, _ *moltbooktest.Server) {
+			t.Setenv("MOLTBOOK_API_KEY", "moltbook_wrong_key")
+		}, "", []string{"whoami"}, ExitAuth},
+		{"not logged in", func(t *testing.T, _ *moltbooktest.Server) {
+			t.Setenv("MOLTBOOK_API_KEY", "")
+			t.Setenv("HOME", t.TempDir())
+		}, "", []string{"whoami"}, ExitAuth},
+		{"rate limited", func(t *testing.T, srv *moltbooktest.Server) {
+			srv.RateLimitNext("/agents/me", 1, 5*time.Minute)
+		}, "", []string{"whoami"}, ExitRateLimited},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(t)
+			if tc.setup != nil {
+				tc.setup(t, srv)
+			}
+			before := len(srv.Posts())
+			code, _, stderr := run(tc.stdin, tc.args...)
+			if code != tc.want {
+				t.Errorf("exit code %d, want %d; stderr:\n%s", code, tc.want, stderr)
+			}
+			if n := len(srv.Posts()); n != before {
+				t.Errorf("created %d posts", n-before)
+			}
+		})
+	}
+}
+
+func TestPostFromStdin(t *testing.T) {
+	srv := newServer(t)
+	code, _, stderr := run("Body from a pipe\n", "post", "--title", "Piped", "--content", "-", "-o", "json")
+	if code != ExitOK {
+		t.Fatalf("exit code %d; stderr:\n%s", code, stderr)
+	}
+	posts := srv.Posts()
+	if last := posts[len(posts)-1]; last.Title != "Piped" || last.Content != "Body from a pipe\n" {
+		t.Errorf("created %+v", last)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	newServer(t)
+
+	t.Run("list table", func(t *testing.T) {
+		_, out, _ := run("", "feed", "--sort", "new", "--limit", "2")
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want a header and 2 rows:\n%s", len(lines), out)
+		}
+		if fields := strings.Fields(lines[0]); !slices.Equal(fields, postHeader) {
+			t.Errorf("header %q, want %q", fields, postHeader)
+		}
+		if !strings.HasPrefix(lines[1], "post_") {
+			t.Errorf("row %q doesn't start with the post ID", lines[1])
+		}
+	})
+
+	t.Run("list json", func(t *testing.T) {
+		_, out, _ := run("", "feed", "--sort", "new", "--limit", "2", "-o", "json")
+		var posts []api.Post
+		if err := json.Unmarshal([]byte(out), &posts); err != nil {
+			t.Fatalf("%v:\n%s", err, out)
+		}
+		if len(posts) != 2 || posts[0].ID == "" {
+			t.Errorf("got %+v, want 2 posts", posts)
+		}
+	})
+
+	t.Run("empty list json", func(t *testing.T) {
+		_, out, _ := run("", "feed", "--offset", "100", "--output", "json")
+		if strings.TrimSpace(out) != "[]" {
+			t.Errorf("got %q, want []", out)
+		}
+	})
+
+	t.Run("list jsonl", func(t *testing.T) {
+		_, out, _ := run("", "feed", "--sort", "new", "--limit", "2", "-o", "jsonl")
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines, want 2:\n%s", len(lines), out)
+		}
+		for _, line := range lines {
+			var p api.Post
+			if err := json.Unmarshal([]byte(line), &p); err != nil || p.ID == "" {
+				t.Errorf("line %q: %v", line, err)
+			}
+		}
+	})
+
+	t.Run("item table", func(t *testing.T) {
+		_, out, _ := run("", "whoami")
+		if !strings.Contains(out, "Name:") || !strings.Contains(out, "molty") {
+			t.Errorf("got:\n%s", out)
+		}
+	})
+
+	t.Run("item json", func(t *testing.T) {
+		_, out, _ := run("", "whoami", "-o", "json")
+		var a api.Agent
+		if err := json.Unmarshal([]byte(out), &a); err != nil || a.Name != "molty" {
+			t.Errorf("got %+v, %v:\n%s", a, err, out)
+		}
+		if strings.Count(out, "\n") < 2 {
+			t.Errorf("json output isn't indented:\n%s", out)
+		}
+	})
+
+	t.Run("item jsonl", func(t *testing.T) {
+		_, out, _ := run("", "whoami", "-o", "jsonl")
+		if strings.Count(out, "\n") != 1 {
+			t.Errorf("want a single line:\n%s", out)
+		}
+		var a api.Agent
+		if err := json.Unmarshal([]byte(out), &a); err != nil || a.Name != "molty" {
+			t.Errorf("got %+v, %v", a, err)
+		}
+	})
+}
@@ -0,0 +1,506 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+func init() {
+	register(&command{name: "feed", args: "[--sort hot|new|top|rising] [--submolt name | --personalized] [--limit n] [--offset n]", summary: "List posts from a feed", run: runFeed})
+	register(&command{name: "post", args: "--title title (--content text|- | --url url) [--submolt name]", summary: "Create a text or link post", run: runPost})
+	register(&command{name: "comment", args: "<post-id> [--parent comment-id] (--content text|- | text...)", summary: "Comment on a post or reply to a comment", run: runComment})
+	register(&command{name: "upvote", args: "<id> [--comment]", summary: "Upvote a post or comment", run: runUpvote})
+	register(&command{name: "search", args: "<query...> [--type posts|comments|agents|all] [--submolt name] [--author name] [--limit n]", summary: "Semantic search", run: runSearch})
+	register(&command{name: "whoami", args: "", summary: "Show the logged-in agent", run: runWhoami})
+	register(&command{name: "profile", args: "[name]", summary: "Show an agent's profile and recent posts", run: runProfile})
+	register(&command{name: "follow", args: "<agent> [--undo]", summary: "Follow or unfollow an agent", run: runFollow})
+	register(&command{name: "subscribe", args: "<submolt> [--undo]", summary: "Subscribe to or unsubscribe from a submolt", run: runSubscribe})
+	register(&command{name: "status", args: "", summary: "Show whether the agent has been claimed", run: runStatus})
+}
+
+var postHeader = []string{"ID", "SCORE", "SUBMOLT", "AUTHOR", "CREATED", "TITLE"}
+
+func postRow(p api.Post) []string {
+	title := p.Title
+	if p.IsLink() && p.URL != "" {
+		title += " <" + p.URL + ">"
+	}
+	return []string{
+		p.ID,
+		strconv.Itoa(p.Upvotes - p.Downvotes),
+		"m/" + p.Submolt.Name,
+		p.Author.Name,
+		p.CreatedAt.Format("2006-01-02 15:04"),
+		truncate(title, 80),
+	}
+}
+
+func postFields(p *api.Post) []field {
+	fields := []field{
+		{"ID", p.ID},
+		{"Title", p.Title},
+		{"Submolt", "m/" + p.Submolt.Name},
+		{"Author", p.Author.Name},
+		{"Score", strconv.Itoa(p.Upvotes - p.Downvotes)},
+		{"Created", p.CreatedAt.Format("2006-01-02 15:04")},
+	}
+	if p.URL != "" {
+		fields = append(fields, field{"URL", p.URL})
+	}
+	if p.Content != "" {
+		fields = append(fields, field{"Content", truncate(p.Content, 200)})
+	}
+	return fields
+}
+
+func agentFields(a *api.Agent) []field {
+	status := "claimed"
+	if !a.IsClaimed {
+		status = "pending claim"
+	}
+	return []field{
+		{"Name", a.Name},
+		{"Description", a.Description},
+		{"Karma", strconv.Itoa(a.Karma)},
+		{"Followers", strconv.Itoa(a.FollowerCount)},
+		{"Following", strconv.Itoa(a.FollowingCount)},
+		{"Status", status},
+	}
+}
+
+// noArgs rejects stray positional arguments.
+func noArgs(rest []string) error {
+	if len(rest) > 0 {
+		return usagef("unexpected argument %q", rest[0])
+	}
+	return nil
+}
+
+// readContent returns s, or all of stdin when s is "-".
+func (e *env) readContent(s string) (string, error) {
+	if s != "-" {
+		return s, nil
+	}
+	data, err := io.ReadAll(e.stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading stdin: %w", err)
+	}
+	return string(data), nil
+}
+
+func runFeed(e *env, args []string) error {
+	var out format
+	fs := e.flags("feed", &out)
+	sortBy := fs.String("sort", "hot", "hot, new, top or rising")
+	submolt := fs.String("submolt", "", "read this submolt's feed")
+	personalized := fs.Bool("personalized", false, "read your subscriptions and follows")
+	limit := fs.Int("limit", 20, "number of posts")
+	offset := fs.Int("offset", 0, "number of posts to skip")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+	switch *sortBy {
+	case "hot", "new", "top", "rising":
+	default:
+		return usagef("unknown sort %q", *sortBy)
+	}
+	if *limit <= 0 || *offset < 0 {
+		return usagef("--limit must be positive and --offset not negative")
+	}
+	if *submolt != "" && *personalized {
+		return usagef("--submolt and --personalized are mutually exclusive")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var posts []api.Post
+	switch {
+	case *submolt != "":
+		posts, err = c.GetSubmoltFeed(e.ctx, strings.TrimPrefix(*submolt, "m/"), *sortBy, *limit, *offset)
+	case *personalized:
+		posts, err = c.GetPersonalizedFeed(e.ctx, *sortBy, *limit, *offset)
+	default:
+		posts, err = c.GetFeed(e.ctx, *sortBy, *limit, *offset)
+	}
+	if err != nil {
+		return err
+	}
+	return writeList(e.stdout, out, posts, postHeader, postRow)
+}
+
+func runPost(e *env, args []string) error {
+	var out format
+	fs := e.flags("post", &out)
+	submolt := fs.String("submolt", "general", "submolt to post in")
+	title := fs.String("title", "", "post title (required)")
+	content := fs.String("content", "", "post body, or - to read it from stdin")
+	link := fs.String("url", "", "share this http(s) URL instead of a body")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*title) == "" {
+		return usagef("--title is required")
+	}
+	if (*content == "") == (*link == "") {
+		return usagef("exactly one of --content and --url is required")
+	}
+	if *link != "" {
+		if err := api.ValidateLinkURL(*link); err != nil {
+			return usagef("%v", err)
+		}
+	}
+	body, err := e.readContent(*content)
+	if err != nil {
+		return err
+	}
+	if *link == "" && strings.TrimSpace(body) == "" {
+		return usagef("missing post content")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	// One key per invocation so transport retries can't double-post
+	ctx := api.WithCallOptions(e.ctx, api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
+	name := strings.TrimPrefix(*submolt, "m/")
+	var post *api.Post
+	if *link != "" {
+		post, err = c.CreateLinkPost(ctx, name, *title, *link)
+	} else {
+		post, err = c.CreatePost(ctx, name, *title, body)
+	}
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		// Older servers don't echo the post; report what was sent
+		post = &api.Post{Title: *title, Content: body, URL: *link}
+		post.Submolt.Name = name
+	}
+	return writeItem(e.stdout, out, post, postFields(post))
+}
+
+func runComment(e *env, args []string) error {
+	var out format
+	fs := e.flags("comment", &out)
+	parent := fs.String("parent", "", "reply to this comment")
+	content := fs.String("content", "", "comment text, or - to read it from stdin")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return usagef("missing post ID")
+	}
+	postID, words := rest[0], rest[1:]
+	if *content != "" && len(words) > 0 {
+		return usagef("give the text either as --content or as arguments, not both")
+	}
+	text := strings.Join(words, " ")
+	if *content != "" {
+		if text, err = e.readContent(*content); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return usagef("missing comment text")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	ctx := api.WithCallOptions(e.ctx, api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
+	var comment *api.Comment
+	if *parent != "" {
+		comment, err = c.CreateReply(ctx, postID, *parent, text)
+	} else {
+		comment, err = c.CreateComment(ctx, postID, text)
+	}
+	if err != nil {
+		return err
+	}
+	if comment == nil {
+		comment = &api.Comment{Content: text, ParentID: *parent}
+	}
+	return writeItem(e.stdout, out, comment, []field{
+		{"ID", comment.ID},
+		{"Post", postID},
+		{"Parent", comment.ParentID},
+		{"Content", truncate(comment.Content, 200)},
+	})
+}
+
+type voteResult struct {
+	ID     string `json:"id"`
+	Target string `json:"target"` // post or comment
+	Vote   string `json:"vote"`
+}
+
+func runUpvote(e *env, args []string) error {
+	var out format
+	fs := e.flags("upvote", &out)
+	isComment := fs.Bool("comment", false, "the ID is a comment rather than a post")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("expected exactly one ID")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	res := voteResult{ID: rest[0], Target: "post", Vote: "up"}
+	if *isComment {
+		res.Target = "comment"
+		err = c.UpvoteComment(e.ctx, res.ID)
+	} else {
+		err = c.UpvotePost(e.ctx, res.ID)
+	}
+	if err != nil {
+		return err
+	}
+	return writeItem(e.stdout, out, res, []field{{"Upvoted", res.Target + " " + res.ID}})
+}
+
+func runSearch(e *env, args []string) error {
+	var out format
+	fs := e.flags("search", &out)
+	kind := fs.String("type", api.SearchTypePosts, "posts, comments, agents or all")
+	submolt := fs.String("submolt", "", "only hits in this submolt")
+	author := fs.String("author", "", "only hits by this agent")
+	limit := fs.Int("limit", 20, "number of results")
+	offset := fs.Int("offset", 0, "number of results to skip")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(rest, " "))
+	if query == "" {
+		return usagef("missing search query")
+	}
+	switch *kind {
+	case api.SearchTypePosts, api.SearchTypeComments, api.SearchTypeAgents, api.SearchTypeAll:
+	default:
+		return usagef("unknown search type %q", *kind)
+	}
+	if *limit <= 0 || *offset < 0 {
+		return usagef("--limit must be positive and --offset not negative")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	results, err := c.Search(e.ctx, api.SearchQuery{
+		Query:   query,
+		Type:    *kind,
+		Submolt: strings.TrimPrefix(*submolt, "m/"),
+		Author:  *author,
+		Limit:   *limit,
+		Offset:  *offset,
+	})
+	if err != nil {
+		return err
+	}
+	return writeList(e.stdout, out, results, []string{"TYPE", "ID", "SIMILARITY", "AUTHOR", "TEXT"}, func(r api.SearchResult) []string {
+		row := []string{string(r.Kind), "", fmt.Sprintf("%.2f", r.Similarity), "", ""}
+		switch r.Kind {
+		case api.SearchKindComment:
+			row[1], row[3], row[4] = r.Comment.ID, r.Comment.Author.Name, truncate(r.Comment.Content, 80)
+		case api.SearchKindAgent:
+			row[1], row[3], row[4] = r.Agent.Name, r.Agent.Name, truncate(r.Agent.Description, 80)
+		default:
+			row[1], row[3], row[4] = r.Post.ID, r.Post.Author.Name, truncate(r.Post.Title, 80)
+		}
+		return row
+	})
+}
+
+func runWhoami(e *env, args []string) error {
+	var out format
+	fs := e.flags("whoami", &out)
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	me, err := c.GetMe(e.ctx)
+	if err != nil {
+		return err
+	}
+	return writeItem(e.stdout, out, me, agentFields(me))
+}
+
+type profileResult struct {
+	Agent       *api.Agent `json:"agent"`
+	RecentPosts []api.Post `json:"recent_posts"`
+}
+
+func runProfile(e *env, args []string) error {
+	var out format
+	fs := e.flags("profile", &out)
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 1 {
+		return usagef("expected at most one agent name")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var name string
+	if len(rest) == 1 {
+		name = rest[0]
+	} else {
+		me, err := c.GetMe(e.ctx)
+		if err != nil {
+			return err
+		}
+		name = me.Name
+	}
+	agent, posts, err := c.GetProfile(e.ctx, name)
+	if err != nil {
+		return err
+	}
+	if posts == nil {
+		posts = []api.Post{}
+	}
+	res := profileResult{Agent: agent, RecentPosts: posts}
+	if err := writeItem(e.stdout, out, res, agentFields(agent)); err != nil {
+		return err
+	}
+	if out != formatTable || len(posts) == 0 {
+		return nil
+	}
+	fmt.Fprintln(e.stdout)
+	return writeList(e.stdout, out, posts, postHeader, postRow)
+}
+
+type followResult struct {
+	Name      string `json:"name"`
+	Following bool   `json:"following"`
+}
+
+func runFollow(e *env, args []string) error {
+	var out format
+	fs := e.flags("follow", &out)
+	undo := fs.Bool("undo", false, "unfollow instead")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("expected exactly one agent name")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	res := followResult{Name: rest[0], Following: !*undo}
+	if *undo {
+		err = c.Unfollow(e.ctx, res.Name)
+	} else {
+		err = c.Follow(e.ctx, res.Name)
+	}
+	if err != nil {
+		return err
+	}
+	verb := "Following"
+	if *undo {
+		verb = "Unfollowed"
+	}
+	return writeItem(e.stdout, out, res, []field{{verb, res.Name}})
+}
+
+type subscribeResult struct {
+	Submolt    string `json:"submolt"`
+	Subscribed bool   `json:"subscribed"`
+}
+
+func runSubscribe(e *env, args []string) error {
+	var out format
+	fs := e.flags("subscribe", &out)
+	undo := fs.Bool("undo", false, "unsubscribe instead")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("expected exactly one submolt")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	res := subscribeResult{Submolt: strings.TrimPrefix(rest[0], "m/"), Subscribed: !*undo}
+	if *undo {
+		err = c.Unsubscribe(e.ctx, res.Submolt)
+	} else {
+		err = c.Subscribe(e.ctx, res.Submolt)
+	}
+	if err != nil {
+		return err
+	}
+	verb := "Subscribed to"
+	if *undo {
+		verb = "Unsubscribed from"
+	}
+	return writeItem(e.stdout, out, res, []field{{verb, "m/" + res.Submolt}})
+}
+
+type statusResult struct {
+	Status string `json:"status"`
+}
+
+func runStatus(e *env, args []string) error {
+	var out format
+	fs := e.flags("status", &out)
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	status, err := c.GetStatus(e.ctx)
+	if err != nil {
+		return err
+	}
+	return writeItem(e.stdout, out, statusResult{Status: status}, []field{{"Status", status}})
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/starkbaknet/moltbook-client/pkg/mcp"
+)
+
+func init() {
+	register(&command{name: "mcp", args: "[--allow tool,... | --allow all] [--raw]", summary: "Serve Moltbook tools over MCP on stdio", run: runMCP})
+}
+
+// allowList collects --allow, which may be repeated or comma-separated.
+type allowList []string
+
+func (a *allowList) String() string { return strings.Join(*a, ",") }
+
+func (a *allowList) Set(s string) error {
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*a = append(*a, name)
+		}
+	}
+	return nil
+}
+
+func runMCP(e *env, args []string) error {
+	var allow allowList
+	fs := e.bareFlags("mcp")
+	fs.Var(&allow, "allow", "write tools clients may call, or all: "+strings.Join(mcp.WriteTools(), ", "))
+	raw := fs.Bool("raw", false, "don't neutralize likely prompt injection in other agents' text")
+	rest, err := e.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(rest); err != nil {
+		return err
+	}
+	for _, name := range allow {
+		if name == "all" {
+			allow = mcp.WriteTools()
+			break
+		}
+	}
+
+	// stdout carries the protocol, so logs go to stderr only
+	logger := slog.New(slog.NewTextHandler(e.stderr, nil))
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	srv, err := mcp.NewServer(c, mcp.Options{AllowWrites: allow, Raw: *raw, Log: logger})
+	if err != nil {
+		return usagef("--allow: %v", err)
+	}
+	if len(allow) == 0 {
+		logger.Info("serving read-only tools; enable writes with --allow")
+	} else {
+		logger.Info("serving tools", "writes", strings.Join(allow, ","))
+	}
+	return srv.Serve(e.ctx, e.stdin, e.stdout)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// format is the value of --output.
+type format string
+
+const (
+	formatTable format = "table"
+	formatJSON  format = "json"
+	formatJSONL format = "jsonl"
+)
+
+func (f *format) String() string {
+	return string(*f)
+}
+
+func (f *format) Set(s string) error {
+	switch v := format(strings.ToLower(s)); v {
+	case formatTable, formatJSON, formatJSONL:
+		*f = v
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (want table, json or jsonl)", s)
+}
+
+// writeList prints items as an aligned table, a JSON array, or one JSON
+// value per line. row renders an item's table cells in header order.
+func writeList[T any](w io.Writer, f format, items []T, header []string, row func(T) []string) error {
+	switch f {
+	case formatJSON:
+		if items == nil {
+			items = []T{} // [] rather than null
+		}
+		return writeJSON(w, items)
+	case formatJSONL:
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, item := range items {
+		cells := row(item)
+		for i, c := range cells {
+			cells[i] = oneLine(c)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// field is one row of a single item's table view.
+type field struct {
+	name, value string
+}
+
+// writeItem prints one value: as "name  value" rows for tables, or as a
+// single JSON value for both JSON formats.
+func writeItem(w io.Writer, f format, v any, fields []field) error {
+	if f != formatTable {
+		if f == formatJSONL {
+			return json.NewEncoder(w).Encode(v)
+		}
+		return writeJSON(w, v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, fl := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", fl.name, oneLine(fl.value))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// oneLine keeps a table cell on its row.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most n runes for table cells.
+func truncate(s string, n int) string {
+	s = oneLine(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
@@ -9,6 +9,20 @@ import (
 type Config struct {
 	APIKey    string `json:"api_key"`
 	AgentName string `json:"agent_name"`
+
+	// LLM enables the drafting assistant in the composers. Optional.
+	LLM *LLMConfig `json:"llm,omitempty"`
+}
+
+// LLMConfig points the drafting assistant at an OpenAI-compatible chat
+// endpoint, e.g. a local Ollama server at http://localhost:11434/v1.
+type LLMConfig struct {
+	BaseURL string `json:"base_url"`
+	Model   string `json:"model"`
+	APIKey  string `json:"api_key,omitempty"` // Not needed for most local servers
+
+	// Persona is added to the system prompt, e.g. to set a tone
+	Persona string `json:"persona,omitempty"`
 }
 
 func GetConfigPath() string {
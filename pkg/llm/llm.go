@@ -0,0 +1,87 @@
+// Package llm drafts posts and replies with a language model. The model sits
+// behind ChatClient, so anything that speaks the OpenAI chat completions API
+// works, e.g. a local Ollama server, and tests can swap in a stub.
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Message is one turn of a chat.
+type Message struct {
+	Role    string `json:"role"` // system, user or assistant
+	Content string `json:"content"`
+}
+
+// ChatClient completes a chat with the assistant's next message.
+type ChatClient interface {
+	Chat(ctx context.Context, messages []Message) (string, error)
+}
+
+// OpenAIClient talks to an OpenAI-compatible /chat/completions endpoint.
+// Create it with NewOpenAIClient.
+type OpenAIClient struct {
+	rest  *resty.Client
+	model string
+}
+
+// NewOpenAIClient builds a client for baseURL, e.g.
+// "http://localhost:11434/v1" for Ollama. apiKey may be empty for local
+// servers.
+func NewOpenAIClient(baseURL, model, apiKey string) *OpenAIClient {
+	c := resty.New()
+	// Local models can take a while on modest hardware
+	c.SetTimeout(2 * time.Minute)
+	c.SetBaseURL(strings.TrimRight(baseURL, "/"))
+	if apiKey != "" {
+		c.SetAuthToken(apiKey)
+	}
+	return &OpenAIClient{rest: c, model: model}
+}
+
+type chatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *OpenAIClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	resp, err := c.rest.R().
+		SetContext(ctx).
+		SetBody(chatRequest{Model: c.model, Messages: messages}).
+		Post("/chat/completions")
+	if err != nil {
+		return "", err
+	}
+	// Decoded by hand since not every local server sets a JSON content type
+	var out chatResponse
+	if err := json.Unmarshal(resp.Body(), &out); err != nil && !resp.IsError() {
+		return "", fmt.Errorf("reading chat response: %w", err)
+	}
+	if resp.IsError() {
+		if out.Error != nil && out.Error.Message != "" {
+			return "", fmt.Errorf("%s: %s", resp.Status(), out.Error.Message)
+		}
+		return "", fmt.Errorf("chat request failed: %s", resp.Status())
+	}
+	if len(out.Choices) == 0 {
+		return "", errors.New("the model returned no choices")
+	}
+	return strings.TrimSpace(out.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/safety"
+)
+
+// flagged lists the prompt-injection findings for one item of a tool
+// result, under the result's "safety" key.
+type flagged struct {
+	Kind     string           `json:"kind"` // post, comment or agent
+	ID       string           `json:"id"`
+	Findings []safety.Finding `json:"findings"`
+}
+
+// screener checks the text other agents wrote in a tool result and, unless
+// raw, replaces it with the neutralized version in place.
+type screener struct {
+	raw     bool
+	flagged []flagged
+}
+
+// result screens the posts, comments and agents a tool returned.
+func (s *screener) result(out map[string]any) {
+	// Sorted so "safety" lists items in the same order every time
+	for _, key := range slices.Sorted(maps.Keys(out)) {
+		switch v := out[key].(type) {
+		case []api.Post:
+			for i := range v {
+				s.post(&v[i])
+			}
+		case *api.Post:
+			s.post(v)
+		case []api.Comment:
+			s.comments(v)
+		case *api.Comment:
+			s.comment(v)
+		case *api.Agent:
+			s.agent(v)
+		case []api.SearchResult:
+			for _, r := range v {
+				switch {
+				case r.Post != nil:
+					s.post(r.Post)
+				case r.Comment != nil:
+					s.comment(r.Comment)
+				case r.Agent != nil:
+					s.agent(r.Agent)
+				}
+			}
+		}
+	}
+}
+
+func (s *screener) post(p *api.Post) {
+	if p != nil {
+		s.text("post", p.ID, &p.Title, &p.Content)
+	}
+}
+
+func (s *screener) comments(cs []api.Comment) {
+	for i := range cs {
+		s.comment(&cs[i])
+	}
+}
+
+func (s *screener) comment(c *api.Comment) {
+	if c != nil {
+		s.text("comment", c.ID, &c.Content)
+		s.comments(c.Replies)
+	}
+}
+
+func (s *screener) agent(a *api.Agent) {
+	if a != nil {
+		s.text("agent", a.Name, &a.Description)
+	}
+}
+
+func (s *screener) text(kind, id string, fields ...*string) {
+	var findings []safety.Finding
+	for _, f := range fields {
+		res := safety.Check(*f)
+		findings = append(findings, res.Findings...)
+		if !s.raw {
+			*f = res.Neutralized
+		}
+	}
+	if len(findings) > 0 {
+		s.flagged = append(s.flagged, flagged{Kind: kind, ID: id, Findings: findings})
+	}
+}
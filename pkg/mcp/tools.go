@@ -0,0 +1,513 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// maxLimit caps page sizes so a single call can't flood the model's context.
+const maxLimit = 50
+
+type tool struct {
+	name        string
+	title       string
+	description string
+	schema      map[string]any
+	write       bool // Needs to be in Options.AllowWrites
+	idempotent  bool // Repeating the call has no further effect
+	run         func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error)
+}
+
+// describe renders the tool for tools/list.
+func (t *tool) describe() map[string]any {
+	return map[string]any{
+		"name":        t.name,
+		"title":       t.title,
+		"description": t.description,
+		"inputSchema": t.schema,
+		"annotations": map[string]any{
+			"title":           t.title,
+			"readOnlyHint":    !t.write,
+			"destructiveHint": false,
+			"idempotentHint":  !t.write || t.idempotent,
+			"openWorldHint":   true,
+		},
+	}
+}
+
+// WriteTools names the tools that change something on Moltbook, i.e. the
+// ones Options.AllowWrites can enable.
+func WriteTools() []string {
+	var names []string
+	for _, t := range allTools {
+		if t.write {
+			names = append(names, t.name)
+		}
+	}
+	return names
+}
+
+func findTool(name string) *tool {
+	for _, t := range allTools {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Schema building blocks
+
+func object(props map[string]any, required ...string) map[string]any {
+	s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func str(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func enum(description, def string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values, "default": def}
+}
+
+func integer(description string, min, max, def int) map[string]any {
+	return map[string]any{"type": "integer", "description": description, "minimum": min, "maximum": max, "default": def}
+}
+
+func boolean(description string) map[string]any {
+	return map[string]any{"type": "boolean", "description": description, "default": false}
+}
+
+// decode reads tool arguments strictly, so a misspelled argument is an error
+// rather than silently ignored.
+func decode(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// page applies defaults and bounds to limit and offset.
+func page(limit, offset *int, def int) error {
+	if *limit == 0 {
+		*limit = def
+	}
+	if *limit < 1 || *limit > maxLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	if *offset < 0 {
+		return errors.New("offset can't be negative")
+	}
+	return nil
+}
+
+func oneOf(value, field string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of %s", field, strings.Join(allowed, ", "))
+}
+
+func required(value, field string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	return nil
+}
+
+// writeCtx gives each write its own idempotency key so transport retries
+// can't double-post.
+func writeCtx(ctx context.Context) context.Context {
+	return api.WithCallOptions(ctx, api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
+}
+
+var allTools = []*tool{
+	{
+		name:        "get_feed",
+		title:       "Get feed",
+		description: "List Moltbook posts from the global feed, a submolt's feed, or the personalized feed of subscriptions and follows.",
+		schema: object(map[string]any{
+			"sort":         enum("Ordering of the posts", "hot", "hot", "new", "top", "rising"),
+			"submolt":      str("Read this submolt's feed, e.g. \"general\""),
+			"personalized": boolean("Read the agent's subscriptions and follows; can't be combined with submolt"),
+			"limit":        integer("Number of posts", 1, maxLimit, 20),
+			"offset":       integer("Number of posts to skip, for paging", 0, 10000, 0),
+		}),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Sort         string `json:"sort"`
+				Submolt      string `json:"submolt"`
+				Personalized bool   `json:"personalized"`
+				Limit        int    `json:"limit"`
+				Offset       int    `json:"offset"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if a.Sort == "" {
+				a.Sort = "hot"
+			}
+			if err := oneOf(a.Sort, "sort", "hot", "new", "top", "rising"); err != nil {
+				return nil, err
+			}
+			if err := page(&a.Limit, &a.Offset, 20); err != nil {
+				return nil, err
+			}
+			submolt := strings.TrimPrefix(a.Submolt, "m/")
+			var posts []api.Post
+			var err error
+			switch {
+			case submolt != "" && a.Personalized:
+				return nil, errors.New("submolt and personalized can't be combined")
+			case submolt != "":
+				posts, err = c.GetSubmoltFeed(ctx, submolt, a.Sort, a.Limit, a.Offset)
+			case a.Personalized:
+				posts, err = c.GetPersonalizedFeed(ctx, a.Sort, a.Limit, a.Offset)
+			default:
+				posts, err = c.GetFeed(ctx, a.Sort, a.Limit, a.Offset)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"posts": nonNil(posts)}, nil
+		},
+	},
+	{
+		name:        "get_post_comments",
+		title:       "Get post comments",
+		description: "Get a post and its comment thread. Replies are nested under their parent comment.",
+		schema: object(map[string]any{
+			"post_id": str("ID of the post"),
+			"sort":    enum("Ordering of the comments", api.CommentSortTop, api.CommentSortTop, api.CommentSortNew, api.CommentSortControversial),
+			"limit":   integer("Number of top-level comments", 1, maxLimit, 20),
+			"offset":  integer("Number of top-level comments to skip, for paging", 0, 10000, 0),
+		}, "post_id"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				PostID string `json:"post_id"`
+				Sort   string `json:"sort"`
+				Limit  int    `json:"limit"`
+				Offset int    `json:"offset"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.PostID, "post_id"); err != nil {
+				return nil, err
+			}
+			if a.Sort == "" {
+				a.Sort = api.CommentSortTop
+			}
+			if err := oneOf(a.Sort, "sort", api.CommentSortTop, api.CommentSortNew, api.CommentSortControversial); err != nil {
+				return nil, err
+			}
+			if err := page(&a.Limit, &a.Offset, 20); err != nil {
+				return nil, err
+			}
+			post, err := c.GetPost(ctx, a.PostID)
+			if err != nil {
+				return nil, err
+			}
+			comments, err := c.GetComments(ctx, a.PostID, api.CommentQuery{Sort: a.Sort, Limit: a.Limit, Offset: a.Offset})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"post": post, "comments": nonNil(comments)}, nil
+		},
+	},
+	{
+		name:        "search",
+		title:       "Search",
+		description: "Semantic search across Moltbook posts, comments and agents. Results carry a similarity score between 0 and 1.",
+		schema: object(map[string]any{
+			"query":   str("What to look for, in natural language"),
+			"type":    enum("What to search", api.SearchTypePosts, api.SearchTypePosts, api.SearchTypeComments, api.SearchTypeAgents, api.SearchTypeAll),
+			"submolt": str("Only results in this submolt"),
+			"author":  str("Only results by this agent"),
+			"limit":   integer("Number of results", 1, maxLimit, 20),
+			"offset":  integer("Number of results to skip, for paging", 0, 10000, 0),
+		}, "query"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Query   string `json:"query"`
+				Type    string `json:"type"`
+				Submolt string `json:"submolt"`
+				Author  string `json:"author"`
+				Limit   int    `json:"limit"`
+				Offset  int    `json:"offset"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.Query, "query"); err != nil {
+				return nil, err
+			}
+			if a.Type == "" {
+				a.Type = api.SearchTypePosts
+			}
+			if err := oneOf(a.Type, "type", api.SearchTypePosts, api.SearchTypeComments, api.SearchTypeAgents, api.SearchTypeAll); err != nil {
+				return nil, err
+			}
+			if err := page(&a.Limit, &a.Offset, 20); err != nil {
+				return nil, err
+			}
+			results, err := c.Search(ctx, api.SearchQuery{
+				Query:   a.Query,
+				Type:    a.Type,
+				Submolt: strings.TrimPrefix(a.Submolt, "m/"),
+				Author:  a.Author,
+				Limit:   a.Limit,
+				Offset:  a.Offset,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"results": nonNil(results)}, nil
+		},
+	},
+	{
+		name:        "get_profile",
+		title:       "Get profile",
+		description: "Get an agent's profile (description, karma, followers) and recent posts. Without a name, returns the agent this server runs as.",
+		schema: object(map[string]any{
+			"name": str("Agent name; omit for yourself"),
+		}),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Name string `json:"name"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			name := strings.TrimPrefix(a.Name, "@")
+			if name == "" {
+				me, err := c.GetMe(ctx)
+				if err != nil {
+					return nil, err
+				}
+				name = me.Name
+			}
+			agent, posts, err := c.GetProfile(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"agent": agent, "recent_posts": nonNil(posts)}, nil
+		},
+	},
+	{
+		name:        "create_post",
+		title:       "Create post",
+		description: "Publish a text post (title and content) or a link post (title and url) in a submolt. Limited to one post per 30 minutes.",
+		write:       true,
+		schema: object(map[string]any{
+			"submolt": map[string]any{"type": "string", "description": "Submolt to post in", "default": "general"},
+			"title":   str("Post title"),
+			"content": str("Markdown body of a text post; give either content or url"),
+			"url":     str("http(s) URL for a link post; give either content or url"),
+		}, "title"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Submolt string `json:"submolt"`
+				Title   string `json:"title"`
+				Content string `json:"content"`
+				URL     string `json:"url"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.Title, "title"); err != nil {
+				return nil, err
+			}
+			if (strings.TrimSpace(a.Content) == "") == (a.URL == "") {
+				return nil, errors.New("exactly one of content and url is required")
+			}
+			submolt := strings.TrimPrefix(a.Submolt, "m/")
+			if submolt == "" {
+				submolt = "general"
+			}
+			var post *api.Post
+			var err error
+			if a.URL != "" {
+				if err := api.ValidateLinkURL(a.URL); err != nil {
+					return nil, err
+				}
+				post, err = c.CreateLinkPost(writeCtx(ctx), submolt, a.Title, a.URL)
+			} else {
+				post, err = c.CreatePost(writeCtx(ctx), submolt, a.Title, a.Content)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if post == nil {
+				// Older servers don't echo the post; report what was sent
+				post = &api.Post{Title: a.Title, Content: a.Content, URL: a.URL}
+				post.Submolt.Name = submolt
+			}
+			return map[string]any{"post": post}, nil
+		},
+	},
+	{
+		name:        "create_comment",
+		title:       "Create comment",
+		description: "Comment on a post, or reply to a comment by giving parent_id. Limited to one comment per 20 seconds.",
+		write:       true,
+		schema: object(map[string]any{
+			"post_id":   str("ID of the post"),
+			"content":   str("Markdown comment text"),
+			"parent_id": str("ID of the comment to reply to; omit for a top-level comment"),
+		}, "post_id", "content"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				PostID   string `json:"post_id"`
+				Content  string `json:"content"`
+				ParentID string `json:"parent_id"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.PostID, "post_id"); err != nil {
+				return nil, err
+			}
+			if err := required(a.Content, "content"); err != nil {
+				return nil, err
+			}
+			var comment *api.Comment
+			var err error
+			if a.ParentID != "" {
+				comment, err = c.CreateReply(writeCtx(ctx), a.PostID, a.ParentID, a.Content)
+			} else {
+				comment, err = c.CreateComment(writeCtx(ctx), a.PostID, a.Content)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if comment == nil {
+				comment = &api.Comment{Content: a.Content, ParentID: a.ParentID}
+			}
+			return map[string]any{"post_id": a.PostID, "comment": comment}, nil
+		},
+	},
+	{
+		name:        "upvote",
+		title:       "Upvote",
+		description: "Upvote a post or a comment. Give exactly one of post_id and comment_id.",
+		write:       true,
+		idempotent:  true,
+		schema: object(map[string]any{
+			"post_id":    str("ID of the post to upvote"),
+			"comment_id": str("ID of the comment to upvote"),
+		}),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				PostID    string `json:"post_id"`
+				CommentID string `json:"comment_id"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if (a.PostID == "") == (a.CommentID == "") {
+				return nil, errors.New("exactly one of post_id and comment_id is required")
+			}
+			if a.CommentID != "" {
+				if err := c.UpvoteComment(ctx, a.CommentID); err != nil {
+					return nil, err
+				}
+				return map[string]any{"comment_id": a.CommentID, "vote": "up"}, nil
+			}
+			if err := c.UpvotePost(ctx, a.PostID); err != nil {
+				return nil, err
+			}
+			return map[string]any{"post_id": a.PostID, "vote": "up"}, nil
+		},
+	},
+	{
+		name:        "follow",
+		title:       "Follow agent",
+		description: "Follow an agent so their posts show up in the personalized feed, or unfollow with undo.",
+		write:       true,
+		idempotent:  true,
+		schema: object(map[string]any{
+			"name": str("Agent name"),
+			"undo": boolean("Unfollow instead"),
+		}, "name"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Name string `json:"name"`
+				Undo bool   `json:"undo"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.Name, "name"); err != nil {
+				return nil, err
+			}
+			name := strings.TrimPrefix(a.Name, "@")
+			var err error
+			if a.Undo {
+				err = c.Unfollow(ctx, name)
+			} else {
+				err = c.Follow(ctx, name)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"name": name, "following": !a.Undo}, nil
+		},
+	},
+	{
+		name:        "subscribe",
+		title:       "Subscribe to submolt",
+		description: "Subscribe to a submolt so its posts show up in the personalized feed, or unsubscribe with undo.",
+		write:       true,
+		idempotent:  true,
+		schema: object(map[string]any{
+			"submolt": str("Submolt name, e.g. \"til\""),
+			"undo":    boolean("Unsubscribe instead"),
+		}, "submolt"),
+		run: func(ctx context.Context, c *api.Client, args json.RawMessage) (any, error) {
+			var a struct {
+				Submolt string `json:"submolt"`
+				Undo    bool   `json:"undo"`
+			}
+			if err := decode(args, &a); err != nil {
+				return nil, err
+			}
+			if err := required(a.Submolt, "submolt"); err != nil {
+				return nil, err
+			}
+			submolt := strings.TrimPrefix(a.Submolt, "m/")
+			var err error
+			if a.Undo {
+				err = c.Unsubscribe(ctx, submolt)
+			} else {
+				err = c.Subscribe(ctx, submolt)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"submolt": submolt, "subscribed": !a.Undo}, nil
+		},
+	},
+}
+
+// nonNil keeps empty lists as [] rather than null in results.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,215 @@
+package moltbooktest
+
+import (
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// AddAgent creates a claimed agent and returns its API key.
+func (f *Fake) AddAgent(name, description string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addAgent(name, description, true).key
+}
+
+func (f *Fake) addAgent(name, description string, claimed bool) *agent {
+	a := &agent{
+		Agent: api.Agent{Name: name, Description: description, IsClaimed: claimed},
+		key:   "moltbook_" + f.genID("key"),
+	}
+	f.agents[name] = a
+	f.keys[a.key] = name
+	f.follows[name] = make(map[string]bool)
+	f.subs[name] = make(map[string]bool)
+	return a
+}
+
+// AddSubmolt creates a community. Existing ones are left untouched.
+func (f *Fake) AddSubmolt(name, displayName, description string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.submolts[name]; ok {
+		return
+	}
+	f.submolts[name] = &submolt{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		CreatedAt:   f.now(),
+	}
+}
+
+// AddPost creates a text post as author in an existing submolt.
+func (f *Fake) AddPost(author, submoltName, title, content string) api.Post {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.addPost(author, submoltName, title, content)
+}
+
+func (f *Fake) addPost(author, submoltName, title, content string) *api.Post {
+	p := &api.Post{
+		ID:        f.genID("post"),
+		Type:      "text",
+		Title:     title,
+		Content:   content,
+		CreatedAt: f.now(),
+	}
+	p.Author.Name = author
+	p.Submolt.Name = submoltName
+	if s, ok := f.submolts[submoltName]; ok {
+		p.Submolt.DisplayName = s.DisplayName
+	}
+	f.posts = append(f.posts, p)
+	return p
+}
+
+// AddComment adds a top-level comment to postID.
+func (f *Fake) AddComment(postID, author, content string) api.Comment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.addComment(postID, author, content)
+}
+
+// AddReply adds a reply to the comment parentID on postID.
+func (f *Fake) AddReply(postID, parentID, author, content string) api.Comment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.addComment(postID, author, content)
+	c.ParentID = parentID
+	return *c
+}
+
+func (f *Fake) addComment(postID, author, content string) *api.Comment {
+	c := &api.Comment{
+		ID:        f.genID("comment"),
+		Content:   content,
+		CreatedAt: f.now(),
+	}
+	c.Author.Name = author
+	f.comments[postID] = append(f.comments[postID], c)
+	return c
+}
+
+// Posts returns a snapshot of every post in creation order.
+func (f *Fake) Posts() []api.Post {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]api.Post, 0, len(f.posts))
+	for _, p := range f.posts {
+		out = append(out, *p)
+	}
+	return out
+}
+
+// Comments returns a snapshot of the comments on postID.
+func (f *Fake) Comments(postID string) []api.Comment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]api.Comment, 0, len(f.comments[postID]))
+	for _, c := range f.comments[postID] {
+		out = append(out, *c)
+	}
+	return out
+}
+
+func (f *Fake) findPost(id string) *api.Post {
+	for _, p := range f.posts {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (f *Fake) findComment(postID, id string) *api.Comment {
+	for _, c := range f.comments[postID] {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// setVote records dir (+1, -1 or 0 to clear) for voter on the post or
+// comment with the given ID, keeping its counters and the author's karma in sync.
+func (f *Fake) setVote(id string, up, down *int, author, voter string, dir int) {
+	if f.votes[id] == nil {
+		f.votes[id] = make(map[string]int)
+	}
+	old := f.votes[id][voter]
+	api.Vote(dir).Apply(api.Vote(old), up, down)
+	if dir == 0 {
+		delete(f.votes[id], voter)
+	} else {
+		f.votes[id][voter] = dir
+	}
+	if a, ok := f.agents[author]; ok {
+		a.Karma += dir - old
+	}
+}
+
+func (f *Fake) votePost(p *api.Post, voter string, dir int) {
+	f.setVote(p.ID, &p.Upvotes, &p.Downvotes, p.Author.Name, voter, dir)
+}
+
+func (f *Fake) findCommentByID(id string) *api.Comment {
+	for _, comments := range f.comments {
+		for _, c := range comments {
+			if c.ID == id {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Fake) seed() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	molty := f.addAgent("molty", "The original lobster. Likes long threads.", true)
+	delete(f.keys, molty.key)
+	molty.key = SeedAPIKey
+	f.keys[SeedAPIKey] = molty.Name
+	for _, name := range []string{"clawdia", "shellby", "pinchy"} {
+		f.addAgent(name, "A seeded test agent.", true)
+	}
+
+	f.submolts["agents"] = &submolt{Name: "agents", DisplayName: "Agents", Description: "Agents talking shop.", CreatedAt: f.now()}
+	f.submolts["til"] = &submolt{Name: "til", DisplayName: "Today I Learned", Description: "Things agents learned today.", CreatedAt: f.now()}
+	f.subs["molty"]["agents"] = true
+	f.follows["molty"]["clawdia"] = true
+	molty.FollowingCount = 1
+	f.agents["clawdia"].FollowerCount = 1
+
+	start := f.now().Add(-48 * time.Hour)
+	seedPosts := []struct{ author, submolt, title, content string }{
+		{"clawdia", "general", "Hello Moltbook", "First post from a freshly claimed agent. What should I read first?"},
+		{"shellby", "agents", "How do you all handle rate limits?", "I keep hitting 429 on comments. Is a 20 second cooldown normal?"},
+		{"pinchy", "til", "TIL the feed has a personalized mode", "Subscribe to a few submolts and /feed only shows those."},
+		{"molty", "general", "Welcome thread", "Introduce yourself below. Humans welcome to lurk."},
+		{"clawdia", "agents", "Memory strategies", "Do you summarise your context or keep a scratchpad file?"},
+	}
+	var posts []*api.Post
+	for i, sp := range seedPosts {
+		p := f.addPost(sp.author, sp.submolt, sp.title, sp.content)
+		p.CreatedAt = start.Add(time.Duration(i) * 6 * time.Hour)
+		posts = append(posts, p)
+	}
+	f.addComment(posts[0].ID, "molty", "Start with the welcome thread!")
+	f.addComment(posts[1].ID, "pinchy", "Yes, comments are limited to one every 20 seconds.")
+	f.addComment(posts[1].ID, "molty", "Back off and honour retry_after_seconds.")
+	hi := f.addComment(posts[3].ID, "shellby", "Hi, I'm Shellby. I mostly write about tooling.")
+	reply := f.addComment(posts[3].ID, "molty", "Welcome Shellby! What are you building?")
+	reply.ParentID = hi.ID
+	nested := f.addComment(posts[3].ID, "shellby", "A retry library that doesn't double-post.")
+	nested.ParentID = reply.ID
+
+	for _, voter := range []string{"molty", "shellby", "pinchy"} {
+		f.votePost(posts[0], voter, 1)
+	}
+	f.votePost(posts[1], "clawdia", 1)
+	f.votePost(posts[3], "clawdia", 1)
+	f.votePost(posts[3], "pinchy", 1)
+}
@@ -0,0 +1,113 @@
+package moltbooktest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Failure describes a canned error the fake returns instead of handling a
+// request, or, with AfterHandling, in place of the real response. Register
+// it with Inject.
+type Failure struct {
+	Method string // Empty matches any method
+	Path   string // Prefix relative to APIPrefix, e.g. "/posts"; empty matches any
+
+	Status     int           // HTTP status to return
+	Message    string        // Error message in the JSON body
+	RetryAfter time.Duration // Sent as retry_after_seconds and Retry-After on 429
+	Drop       bool          // Close the connection without a response
+	Delay      time.Duration // Sleep before responding
+
+	// AfterHandling handles the request first and then fails anyway, like
+	// a response lost on the way back. A retried write must then carry the
+	// same Idempotency-Key not to happen twice.
+	AfterHandling bool
+
+	// Times is how many matching requests fail; 0 means once, < 0 forever.
+	Times int
+}
+
+// Inject queues a failure. Failures are matched in the order they were added.
+func (f *Fake) Inject(fl Failure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if fl.Times == 0 {
+		fl.Times = 1
+	}
+	f.failures = append(f.failures, &fl)
+}
+
+// RateLimitNext makes the next n requests to path answer 429.
+func (f *Fake) RateLimitNext(path string, n int, retryAfter time.Duration) {
+	f.Inject(Failure{
+		Path:       path,
+		Status:     http.StatusTooManyRequests,
+		Message:    "Rate limit exceeded",
+		RetryAfter: retryAfter,
+		Times:      n,
+	})
+}
+
+// ClearFailures drops all pending injected failures.
+func (f *Fake) ClearFailures() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = nil
+}
+
+func (f *Fake) takeFailure(method, path string) *Failure {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, fl := range f.failures {
+		if fl.Method != "" && fl.Method != method {
+			continue
+		}
+		if fl.Path != "" && !strings.HasPrefix(path, fl.Path) {
+			continue
+		}
+		if fl.Times > 0 {
+			fl.Times--
+			if fl.Times == 0 {
+				f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			}
+		}
+		return fl
+	}
+	return nil
+}
+
+func (fl *Failure) write(w http.ResponseWriter) {
+	if fl.Delay > 0 {
+		time.Sleep(fl.Delay)
+	}
+	if fl.Drop {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	status := fl.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	msg := fl.Message
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	if status == http.StatusTooManyRequests {
+		secs := int(fl.RetryAfter.Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+		writeJSON(w, status, map[string]any{
+			"success":             false,
+			"error":               msg,
+			"retry_after_seconds": secs,
+		})
+		return
+	}
+	writeError(w, status, msg, "")
+}
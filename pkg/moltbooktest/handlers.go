@@ -0,0 +1,615 @@
+package moltbooktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, me *agent)
+
+func (f *Fake) routes() {
+	f.mux = http.NewServeMux()
+	handle := func(pattern string, h handlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		f.mux.HandleFunc(method+" "+APIPrefix+path, f.auth(h))
+	}
+
+	f.mux.HandleFunc("POST "+APIPrefix+"/agents/register", f.register)
+	handle("GET /agents/me", f.getMe)
+	handle("PATCH /agents/me", f.updateMe)
+	handle("GET /agents/profile", f.getProfile)
+	handle("GET /agents/status", f.getStatus)
+	handle("POST /agents/{name}/follow", f.follow)
+	handle("DELETE /agents/{name}/follow", f.unfollow)
+	handle("GET /agents/{name}/followers", f.listFollows(false))
+	handle("GET /agents/{name}/following", f.listFollows(true))
+
+	handle("GET /posts", f.listPosts)
+	handle("POST /posts", f.createPost)
+	handle("GET /posts/{id}", f.getPost)
+	handle("DELETE /posts/{id}", f.deletePost)
+	handle("POST /posts/{id}/upvote", f.votePostHandler(1))
+	handle("POST /posts/{id}/downvote", f.votePostHandler(-1))
+	handle("DELETE /posts/{id}/vote", f.votePostHandler(0))
+	handle("POST /comments/{id}/upvote", f.voteCommentHandler(1))
+	handle("POST /comments/{id}/downvote", f.voteCommentHandler(-1))
+	handle("DELETE /comments/{id}/vote", f.voteCommentHandler(0))
+	handle("GET /posts/{id}/comments", f.listComments)
+	handle("POST /posts/{id}/comments", f.createComment)
+
+	handle("GET /feed", f.personalFeed)
+	handle("GET /submolts", f.listSubmolts)
+	handle("POST /submolts", f.createSubmolt)
+	handle("GET /submolts/{name}", f.getSubmolt)
+	handle("GET /submolts/{name}/feed", f.submoltFeed)
+	handle("POST /submolts/{name}/subscribe", f.subscribe)
+	handle("DELETE /submolts/{name}/subscribe", f.unsubscribe)
+
+	handle("GET /search", f.search)
+
+	f.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Endpoint not found", "")
+	})
+}
+
+// auth resolves the caller from either Authorization: Bearer or X-API-Key.
+func (f *Fake) auth(h handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if key == "" {
+			key = r.Header.Get("X-API-Key")
+		}
+		name, ok := f.keys[key]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Invalid or missing API key", "Register at /agents/register to get one")
+			return
+		}
+		h(w, r, f.agents[name])
+	}
+}
+
+func decode(r *http.Request, v any) bool {
+	return json.NewDecoder(r.Body).Decode(v) == nil
+}
+
+func (f *Fake) register(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if !decode(r, &req) || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required", "")
+		return
+	}
+	if _, exists := f.agents[req.Name]; exists {
+		writeError(w, http.StatusConflict, "Agent name already taken", "Try a different name")
+		return
+	}
+	a := f.addAgent(req.Name, req.Description, false)
+	out := a.Agent
+	out.APIKey = a.key
+	out.ClaimURL = "https://www.moltbook.com/claim/" + a.key
+	out.VerificationCode = "reef-" + strconv.Itoa(f.nextID)
+	f.ok(w, http.StatusCreated, map[string]any{"agent": out})
+}
+
+func (f *Fake) getMe(w http.ResponseWriter, r *http.Request, me *agent) {
+	f.ok(w, http.StatusOK, map[string]any{"agent": me.Agent})
+}
+
+func (f *Fake) updateMe(w http.ResponseWriter, r *http.Request, me *agent) {
+	var req struct {
+		Description *string `json:"description"`
+	}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body", "")
+		return
+	}
+	if req.Description != nil {
+		me.Description = *req.Description
+	}
+	f.ok(w, http.StatusOK, map[string]any{"agent": me.Agent})
+}
+
+func (f *Fake) getProfile(w http.ResponseWriter, r *http.Request, me *agent) {
+	a, ok := f.agents[r.URL.Query().Get("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Agent not found", "")
+		return
+	}
+	var recent []api.Post
+	for _, p := range sortPosts(f.posts, "new") {
+		if p.Author.Name == a.Name {
+			recent = append(recent, *p)
+		}
+	}
+	out := a.Agent
+	out.IsFollowing = f.follows[me.Name][a.Name]
+	f.ok(w, http.StatusOK, map[string]any{"agent": out, "recentPosts": recent})
+}
+
+func (f *Fake) getStatus(w http.ResponseWriter, r *http.Request, me *agent) {
+	status := "pending_claim"
+	if me.IsClaimed {
+		status = "claimed"
+	}
+	f.ok(w, http.StatusOK, map[string]any{"status": status})
+}
+
+func (f *Fake) follow(w http.ResponseWriter, r *http.Request, me *agent) {
+	target, ok := f.agents[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Agent not found", "")
+		return
+	}
+	if target.Name == me.Name {
+		writeError(w, http.StatusBadRequest, "You can't follow yourself", "")
+		return
+	}
+	if !f.follows[me.Name][target.Name] {
+		f.follows[me.Name][target.Name] = true
+		me.FollowingCount++
+		target.FollowerCount++
+	}
+	f.ok(w, http.StatusOK, map[string]any{"message": "Following " + target.Name})
+}
+
+func (f *Fake) unfollow(w http.ResponseWriter, r *http.Request, me *agent) {
+	target, ok := f.agents[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Agent not found", "")
+		return
+	}
+	if f.follows[me.Name][target.Name] {
+		delete(f.follows[me.Name], target.Name)
+		me.FollowingCount--
+		target.FollowerCount--
+	}
+	f.ok(w, http.StatusOK, map[string]any{"message": "Unfollowed " + target.Name})
+}
+
+// listFollows lists who follows {name}, or with following set, whom {name}
+// follows. Agents are sorted by name and flagged as followed by the caller.
+func (f *Fake) listFollows(following bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, me *agent) {
+		target, ok := f.agents[r.PathValue("name")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Agent not found", "")
+			return
+		}
+		var names []string
+		if following {
+			for name := range f.follows[target.Name] {
+				names = append(names, name)
+			}
+		} else {
+			for name, follows := range f.follows {
+				if follows[target.Name] {
+					names = append(names, name)
+				}
+			}
+		}
+		sort.Strings(names)
+		out := make([]*api.Agent, 0, len(names))
+		for _, name := range names {
+			a := f.agents[name].Agent
+			a.IsFollowing = f.follows[me.Name][name]
+			out = append(out, &a)
+		}
+		f.ok(w, http.StatusOK, map[string]any{"agents": page(out, r.URL.Query(), 20)})
+	}
+}
+
+func (f *Fake) listPosts(w http.ResponseWriter, r *http.Request, me *agent) {
+	q := r.URL.Query()
+	posts := f.posts
+	if name := q.Get("submolt"); name != "" {
+		posts = filterPosts(posts, func(p *api.Post) bool { return p.Submolt.Name == name })
+	}
+	f.ok(w, http.StatusOK, map[string]any{"posts": page(sortPosts(posts, q.Get("sort")), q, 25)})
+}
+
+func (f *Fake) createPost(w http.ResponseWriter, r *http.Request, me *agent) {
+	var req struct {
+		Submolt string `json:"submolt"`
+		Title   string `json:"title"`
+		Content string `json:"content"`
+		URL     string `json:"url"`
+	}
+	if !decode(r, &req) || req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required", "")
+		return
+	}
+	if req.Content == "" && req.URL == "" {
+		writeError(w, http.StatusBadRequest, "content or url is required", "")
+		return
+	}
+	if _, ok := f.submolts[req.Submolt]; !ok {
+		writeError(w, http.StatusNotFound, "Submolt not found", "")
+		return
+	}
+	p := f.addPost(me.Name, req.Submolt, req.Title, req.Content)
+	if req.URL != "" {
+		p.Type = "link"
+		p.URL = req.URL
+	}
+	f.ok(w, http.StatusCreated, map[string]any{"post": p})
+}
+
+func (f *Fake) getPost(w http.ResponseWriter, r *http.Request, me *agent) {
+	p := f.findPost(r.PathValue("id"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Post not found", "")
+		return
+	}
+	f.ok(w, http.StatusOK, map[string]any{"post": p})
+}
+
+func (f *Fake) deletePost(w http.ResponseWriter, r *http.Request, me *agent) {
+	id := r.PathValue("id")
+	p := f.findPost(id)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Post not found", "")
+		return
+	}
+	if p.Author.Name != me.Name {
+		writeError(w, http.StatusForbidden, "You can only delete your own posts", "")
+		return
+	}
+	f.posts = filterPosts(f.posts, func(p *api.Post) bool { return p.ID != id })
+	delete(f.comments, id)
+	delete(f.votes, id)
+	f.ok(w, http.StatusOK, map[string]any{"message": "Post deleted"})
+}
+
+var voteMessages = map[int]string{1: "Upvoted! 🦞", -1: "Downvoted", 0: "Vote removed"}
+
+func (f *Fake) votePostHandler(dir int) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, me *agent) {
+		p := f.findPost(r.PathValue("id"))
+		if p == nil {
+			writeError(w, http.StatusNotFound, "Post not found", "")
+			return
+		}
+		f.votePost(p, me.Name, dir)
+		f.ok(w, http.StatusOK, map[string]any{"message": voteMessages[dir]})
+	}
+}
+
+func (f *Fake) voteCommentHandler(dir int) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, me *agent) {
+		c := f.findCommentByID(r.PathValue("id"))
+		if c == nil {
+			writeError(w, http.StatusNotFound, "Comment not found", "")
+			return
+		}
+		f.setVote(c.ID, &c.Upvotes, &c.Downvotes, c.Author.Name, me.Name, dir)
+		f.ok(w, http.StatusOK, map[string]any{"message": voteMessages[dir]})
+	}
+}
+
+func (f *Fake) listComments(w http.ResponseWriter, r *http.Request, me *agent) {
+	id := r.PathValue("id")
+	if f.findPost(id) == nil {
+		writeError(w, http.StatusNotFound, "Post not found", "")
+		return
+	}
+	q := r.URL.Query()
+	f.ok(w, http.StatusOK, map[string]any{"comments": page(sortComments(f.comments[id], q.Get("sort")), q, 0)})
+}
+
+func (f *Fake) createComment(w http.ResponseWriter, r *http.Request, me *agent) {
+	id := r.PathValue("id")
+	if f.findPost(id) == nil {
+		writeError(w, http.StatusNotFound, "Post not found", "")
+		return
+	}
+	var req struct {
+		Content  string `json:"content"`
+		ParentID string `json:"parent_id"`
+	}
+	if !decode(r, &req) || req.Content == "" {
+		writeError(w, http.StatusBadRequest, "content is required", "")
+		return
+	}
+	if req.ParentID != "" && f.findComment(id, req.ParentID) == nil {
+		writeError(w, http.StatusNotFound, "Parent comment not found", "")
+		return
+	}
+	c := f.addComment(id, me.Name, req.Content)
+	c.ParentID = req.ParentID
+	f.ok(w, http.StatusCreated, map[string]any{"comment": c})
+}
+
+// personalFeed returns posts from subscribed submolts and followed agents.
+func (f *Fake) personalFeed(w http.ResponseWriter, r *http.Request, me *agent) {
+	posts := filterPosts(f.posts, func(p *api.Post) bool {
+		return f.subs[me.Name][p.Submolt.Name] || f.follows[me.Name][p.Author.Name]
+	})
+	q := r.URL.Query()
+	f.ok(w, http.StatusOK, map[string]any{"posts": page(sortPosts(posts, q.Get("sort")), q, 25)})
+}
+
+func (f *Fake) listSubmolts(w http.ResponseWriter, r *http.Request, me *agent) {
+	q := r.URL.Query()
+	var out []*api.Submolt
+	for _, s := range f.submolts {
+		v := f.submoltView(s, me)
+		out = append(out, &v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		switch q.Get("sort") {
+		case "new":
+			if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+				return out[i].CreatedAt.After(out[j].CreatedAt)
+			}
+		case "name":
+		default:
+			if out[i].SubscriberCount != out[j].SubscriberCount {
+				return out[i].SubscriberCount > out[j].SubscriberCount
+			}
+		}
+		return out[i].Name < out[j].Name
+	})
+	f.ok(w, http.StatusOK, map[string]any{"submolts": page(out, q, 50)})
+}
+
+func (f *Fake) getSubmolt(w http.ResponseWriter, r *http.Request, me *agent) {
+	s, ok := f.submolts[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Submolt not found", "")
+		return
+	}
+	f.ok(w, http.StatusOK, map[string]any{"submolt": f.submoltView(s, me)})
+}
+
+func (f *Fake) createSubmolt(w http.ResponseWriter, r *http.Request, me *agent) {
+	var req struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+		Description string `json:"description"`
+	}
+	if !decode(r, &req) || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required", "")
+		return
+	}
+	if !validSubmoltName(req.Name) {
+		writeError(w, http.StatusBadRequest, "Invalid submolt name", "Use 2-24 lowercase letters, digits or underscores")
+		return
+	}
+	if _, exists := f.submolts[req.Name]; exists {
+		writeError(w, http.StatusConflict, "Submolt already exists", "")
+		return
+	}
+	s := &submolt{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Owner:       me.Name,
+		CreatedAt:   f.now(),
+	}
+	if s.DisplayName == "" {
+		s.DisplayName = req.Name
+	}
+	f.submolts[s.Name] = s
+	// Owners are subscribed to their own community
+	f.subs[me.Name][s.Name] = true
+	f.ok(w, http.StatusCreated, map[string]any{"submolt": f.submoltView(s, me)})
+}
+
+func validSubmoltName(name string) bool {
+	if len(name) < 2 || len(name) > 24 {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// submoltView is s as seen by me, with live subscriber counts.
+func (f *Fake) submoltView(s *submolt, me *agent) api.Submolt {
+	out := api.Submolt{
+		Name:         s.Name,
+		DisplayName:  s.DisplayName,
+		Description:  s.Description,
+		Owner:        s.Owner,
+		CreatedAt:    s.CreatedAt,
+		IsSubscribed: f.subs[me.Name][s.Name],
+	}
+	for _, subs := range f.subs {
+		if subs[s.Name] {
+			out.SubscriberCount++
+		}
+	}
+	return out
+}
+
+func (f *Fake) submoltFeed(w http.ResponseWriter, r *http.Request, me *agent) {
+	name := r.PathValue("name")
+	if _, ok := f.submolts[name]; !ok {
+		writeError(w, http.StatusNotFound, "Submolt not found", "")
+		return
+	}
+	posts := filterPosts(f.posts, func(p *api.Post) bool { return p.Submolt.Name == name })
+	q := r.URL.Query()
+	f.ok(w, http.StatusOK, map[string]any{"posts": page(sortPosts(posts, q.Get("sort")), q, 25)})
+}
+
+func (f *Fake) subscribe(w http.ResponseWriter, r *http.Request, me *agent) {
+	name := r.PathValue("name")
+	if _, ok := f.submolts[name]; !ok {
+		writeError(w, http.StatusNotFound, "Submolt not found", "")
+		return
+	}
+	f.subs[me.Name][name] = true
+	f.ok(w, http.StatusOK, map[string]any{"message": "Subscribed to m/" + name})
+}
+
+func (f *Fake) unsubscribe(w http.ResponseWriter, r *http.Request, me *agent) {
+	name := r.PathValue("name")
+	if _, ok := f.submolts[name]; !ok {
+		writeError(w, http.StatusNotFound, "Submolt not found", "")
+		return
+	}
+	delete(f.subs[me.Name], name)
+	f.ok(w, http.StatusOK, map[string]any{"message": "Unsubscribed from m/" + name})
+}
+
+// search is a crude stand-in for semantic search: similarity is the share
+// of query words found in the hit's text. type, submolt and author filter
+// the hits like the real endpoint does.
+func (f *Fake) search(w http.ResponseWriter, r *http.Request, me *agent) {
+	q := r.URL.Query()
+	words := strings.Fields(strings.ToLower(q.Get("q")))
+	if len(words) == 0 {
+		writeError(w, http.StatusBadRequest, "q is required", "")
+		return
+	}
+	searchType := q.Get("type")
+	if searchType == "" {
+		searchType = api.SearchTypePosts
+	}
+	want := func(t string) bool { return searchType == t || searchType == api.SearchTypeAll }
+	submoltName, author := q.Get("submolt"), q.Get("author")
+
+	var results []*api.SearchResult
+	for _, p := range f.posts {
+		if submoltName != "" && p.Submolt.Name != submoltName {
+			continue
+		}
+		if want(api.SearchTypePosts) && (author == "" || p.Author.Name == author) {
+			if sim := similarity(words, p.Title+" "+p.Content); sim > 0 {
+				hit := *p
+				hit.Similarity = sim
+				results = append(results, &api.SearchResult{Kind: api.SearchKindPost, Similarity: sim, Post: &hit})
+			}
+		}
+		if !want(api.SearchTypeComments) {
+			continue
+		}
+		for _, c := range f.comments[p.ID] {
+			if author != "" && c.Author.Name != author {
+				continue
+			}
+			if sim := similarity(words, c.Content); sim > 0 {
+				hit := *c
+				results = append(results, &api.SearchResult{Kind: api.SearchKindComment, Similarity: sim, Comment: &hit, PostID: p.ID})
+			}
+		}
+	}
+	// Agents don't live in a submolt
+	if want(api.SearchTypeAgents) && submoltName == "" {
+		// Agents live in a map; go by name so pages stay stable
+		names := make([]string, 0, len(f.agents))
+		for name := range f.agents {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			a := f.agents[name]
+			if author != "" && a.Name != author {
+				continue
+			}
+			if sim := similarity(words, a.Name+" "+a.Description); sim > 0 {
+				hit := a.Agent
+				results = append(results, &api.SearchResult{Kind: api.SearchKindAgent, Similarity: sim, Agent: &hit})
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Similarity > results[j].Similarity })
+	f.ok(w, http.StatusOK, map[string]any{"query": q.Get("q"), "results": page(results, q, 0), "count": len(results)})
+}
+
+func similarity(words []string, text string) float64 {
+	text = strings.ToLower(text)
+	hits := 0
+	for _, w := range words {
+		if strings.Contains(text, w) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(words))
+}
+
+func filterPosts(posts []*api.Post, keep func(*api.Post) bool) []*api.Post {
+	var out []*api.Post
+	for _, p := range posts {
+		if keep(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// sortPosts returns a sorted copy. "hot" and "rising" weight score by age
+// roughly the way the real ranking does; "top" is raw score.
+func sortPosts(posts []*api.Post, by string) []*api.Post {
+	out := append([]*api.Post(nil), posts...)
+	score := func(p *api.Post) int { return p.Upvotes - p.Downvotes }
+	switch by {
+	case "new":
+		sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	case "top":
+		sort.SliceStable(out, func(i, j int) bool { return score(out[i]) > score(out[j]) })
+	default:
+		var newest time.Time
+		for _, p := range out {
+			if p.CreatedAt.After(newest) {
+				newest = p.CreatedAt
+			}
+		}
+		hot := func(p *api.Post) float64 {
+			return float64(score(p)) - newest.Sub(p.CreatedAt).Hours()/2
+		}
+		sort.SliceStable(out, func(i, j int) bool { return hot(out[i]) > hot(out[j]) })
+	}
+	return out
+}
+
+// sortComments returns a sorted copy. Without a sort the creation order is kept.
+func sortComments(comments []*api.Comment, by string) []*api.Comment {
+	out := append([]*api.Comment(nil), comments...)
+	switch by {
+	case "top":
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].Upvotes-out[i].Downvotes > out[j].Upvotes-out[j].Downvotes
+		})
+	case "new":
+		sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	case "controversial":
+		// Lots of votes, evenly split
+		controversy := func(c *api.Comment) int { return min(c.Upvotes, c.Downvotes) }
+		sort.SliceStable(out, func(i, j int) bool { return controversy(out[i]) > controversy(out[j]) })
+	}
+	return out
+}
+
+// page applies limit/offset from the query, copying items out. defLimit is
+// used when no limit is given; 0 means everything.
+func page[T any](items []*T, q url.Values, defLimit int) []T {
+	get := func(key string, def int) int {
+		if n, err := strconv.Atoi(q.Get(key)); err == nil && n >= 0 {
+			return n
+		}
+		return def
+	}
+	limit, offset := get("limit", defLimit), get("offset", 0)
+	if limit == 0 {
+		limit = len(items)
+	}
+	out := []T{}
+	for i := offset; i < len(items) && len(out) < limit; i++ {
+		out = append(out, *items[i])
+	}
+	return out
+}
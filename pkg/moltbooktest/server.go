@@ -0,0 +1,213 @@
+// Package moltbooktest provides an in-memory fake of the Moltbook API for
+// tests and offline development.
+package moltbooktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// APIPrefix is where the fake mounts the API, mirroring the real base URL.
+const APIPrefix = "/api/v1"
+
+// Shape selects how successful responses are encoded. The real API is not
+// consistent, which is why the client decodes via api.FlexibleResponse.
+type Shape uint
+
+const (
+	// ShapeUnwrapped puts payload fields at the root: {"success":true,"posts":[...]}
+	ShapeUnwrapped Shape = iota
+	// ShapeWrapped nests them under data: {"success":true,"data":{"posts":[...]}}
+	ShapeWrapped
+)
+
+// Option configures a Fake.
+type Option func(*Fake)
+
+func WithShape(s Shape) Option {
+	return func(f *Fake) {
+		f.shape = s
+	}
+}
+
+// WithSeed pre-populates the fake with a few agents, submolts, posts and
+// comments. The agent "molty" can be used with SeedAPIKey.
+func WithSeed() Option {
+	return func(f *Fake) {
+		f.seed()
+	}
+}
+
+// SeedAPIKey authenticates as the seeded agent "molty".
+const SeedAPIKey = "moltbook_test_molty"
+
+type agent struct {
+	api.Agent
+	key string
+}
+
+type submolt struct {
+	Name        string
+	DisplayName string
+	Description string
+	Owner       string
+	CreatedAt   time.Time
+}
+
+// Fake is an in-memory Moltbook implementing http.Handler. All methods are
+// safe for concurrent use.
+type Fake struct {
+	mu    sync.Mutex
+	shape Shape
+	now   func() time.Time
+
+	agents   map[string]*agent // by name
+	keys     map[string]string // api key -> agent name
+	submolts map[string]*submolt
+	posts    []*api.Post // creation order
+	comments map[string][]*api.Comment
+	votes    map[string]map[string]int // post ID -> agent -> +1/-1
+	follows  map[string]map[string]bool
+	subs     map[string]map[string]bool // agent -> submolt
+	nextID   int
+
+	failures []*Failure
+	replies  map[string]*httptest.ResponseRecorder // by idempotencyKey
+	mux      *http.ServeMux
+}
+
+func New(opts ...Option) *Fake {
+	f := &Fake{
+		now:      time.Now,
+		agents:   make(map[string]*agent),
+		keys:     make(map[string]string),
+		submolts: make(map[string]*submolt),
+		comments: make(map[string][]*api.Comment),
+		votes:    make(map[string]map[string]int),
+		follows:  make(map[string]map[string]bool),
+		subs:     make(map[string]map[string]bool),
+		replies:  make(map[string]*httptest.ResponseRecorder),
+	}
+	f.routes()
+	// Every real instance has the default community
+	f.AddSubmolt("general", "General", "The default submolt for everything.")
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Server is a Fake listening on a local httptest server.
+type Server struct {
+	*Fake
+	*httptest.Server
+}
+
+// NewServer starts a Fake on a random local port. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	f := New(opts...)
+	return &Server{Fake: f, Server: httptest.NewServer(f)}
+}
+
+// BaseURL is the value to pass to api.WithBaseURL or MOLTBOOK_BASE_URL.
+func (s *Server) BaseURL() string {
+	return s.URL + APIPrefix
+}
+
+// Client returns an api.Client pointed at the server.
+func (s *Server) Client(apiKey string, opts ...api.Option) *api.Client {
+	return api.NewClient(apiKey, append([]api.Option{api.WithBaseURL(s.BaseURL())}, opts...)...)
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, APIPrefix)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found", "")
+		return
+	}
+	fl := f.takeFailure(r.Method, path)
+	if fl != nil && !fl.AfterHandling {
+		fl.write(w)
+		return
+	}
+
+	rec := f.handle(r)
+	if fl != nil {
+		fl.write(w)
+		return
+	}
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	w.Write(rec.Body.Bytes())
+}
+
+// handle runs the request against the fake. A write carrying an
+// Idempotency-Key that was already handled gets the first response again,
+// as the real API promises, instead of happening twice.
+func (f *Fake) handle(r *http.Request) *httptest.ResponseRecorder {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := idempotencyKey(r)
+	if rec := f.replies[key]; key != "" && rec != nil {
+		rec.Header().Set("Idempotent-Replayed", "true")
+		return rec
+	}
+	rec := httptest.NewRecorder()
+	f.mux.ServeHTTP(rec, r)
+	// Server errors may be retried for real
+	if key != "" && rec.Code < 500 {
+		f.replies[key] = rec
+	}
+	return rec
+}
+
+// idempotencyKey scopes a request's Idempotency-Key to its caller and
+// endpoint. It is empty for reads and requests without a key.
+func idempotencyKey(r *http.Request) string {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" || r.Method == http.MethodGet {
+		return ""
+	}
+	return strings.Join([]string{r.Header.Get("Authorization"), r.Header.Get("X-API-Key"), r.Method, r.URL.Path, key}, "\n")
+}
+
+func (f *Fake) genID(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s_%d", prefix, f.nextID)
+}
+
+// ok writes a success response in the configured shape.
+func (f *Fake) ok(w http.ResponseWriter, status int, fields map[string]any) {
+	body := map[string]any{"success": true}
+	if f.shape == ShapeWrapped {
+		body["data"] = fields
+	} else {
+		for k, v := range fields {
+			body[k] = v
+		}
+	}
+	writeJSON(w, status, body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg, hint string) {
+	body := map[string]any{"success": false, "error": msg}
+	if hint != "" {
+		body["hint"] = hint
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
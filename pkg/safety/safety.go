@@ -0,0 +1,499 @@
+// Package safety flags prompt-injection attempts in text written by other
+// agents, such as posts and comments, before it reaches a language model.
+//
+// Check finds instruction overrides ("ignore previous instructions"), fake
+// chat-template and role markers, hidden unicode, encoded payloads and
+// requests for secrets. Alongside the findings it returns a neutralized copy
+// of the text, with hidden characters stripped and high-severity passages
+// replaced by a marker, which is what should be handed to a model. Passages
+// of lower severity are only reported, since ordinary posts trip them too.
+//
+// The checks are heuristics: they catch the common patterns, not every
+// possible attack, so treat neutralized text as untrusted all the same.
+package safety
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Category groups findings by the kind of attack.
+type Category string
+
+const (
+	CategoryOverride Category = "instruction_override" // "ignore previous instructions"
+	CategoryRole     Category = "role_injection"       // Fake system turns and chat-template tokens
+	CategoryHidden   Category = "hidden_unicode"       // Zero-width, bidi and tag characters
+	CategoryEncoded  Category = "encoded_payload"      // base64 or hex that decodes to text
+	CategorySecrets  Category = "secret_request"       // Asks for API keys, prompts, credentials
+)
+
+// label is how a category reads in markers and summaries.
+func (c Category) label() string {
+	return strings.ReplaceAll(string(c), "_", " ")
+}
+
+// Severity says how likely a finding is an actual attack.
+type Severity int
+
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	}
+	return "none"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is one suspicious passage.
+type Finding struct {
+	Category Category `json:"category"`
+	Severity Severity `json:"severity"`
+	Detail   string   `json:"detail"`
+
+	// Excerpt is the matched text, or what hidden or encoded text decodes to
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// Result is what Check found in a text.
+type Result struct {
+	Findings    []Finding
+	Neutralized string // The text with hidden characters and high-severity passages removed
+}
+
+// Flagged reports whether anything was found.
+func (r Result) Flagged() bool {
+	return len(r.Findings) > 0
+}
+
+// Severity is the highest severity among the findings, or zero.
+func (r Result) Severity() Severity {
+	var max Severity
+	for _, f := range r.Findings {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max
+}
+
+// Summary lists the categories found, e.g. "instruction override, hidden
+// unicode", for badges and log lines.
+func (r Result) Summary() string {
+	var labels []string
+	seen := make(map[Category]bool)
+	for _, f := range r.Findings {
+		if !seen[f.Category] {
+			seen[f.Category] = true
+			labels = append(labels, f.Category.label())
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Scan returns just the findings of Check.
+func Scan(text string) []Finding {
+	return Check(text).Findings
+}
+
+// Neutralize returns just the neutralized text of Check.
+func Neutralize(text string) string {
+	return Check(text).Neutralized
+}
+
+// pattern is a phrase-level check.
+type pattern struct {
+	re       *regexp.Regexp
+	group    int // Submatch that is flagged, 0 for the whole match
+	category Category
+	severity Severity
+	detail   string
+}
+
+const (
+	// secretTarget is something only the reader has, addressed to it: "your
+	// API key", "the system prompt". secretEnd makes sure the noun phrase
+	// ends there, so "your password manager" is left alone.
+	secretTarget = `(?:(?:your|ur)\s+(?:(?:moltbook|openai|anthropic|own|full|current|secret|private|hidden|initial|original|real|exact)\s+)?(?:api[\s_-]?keys?|secret\s+keys?|access\s+tokens?|auth(?:entication)?\s+tokens?|bearer\s+tokens?|passwords?|credentials|private\s+keys?|seed\s+phrases?|system\s+prompts?|(?:env|environment)\s+variables|\.env|credentials\.json)` +
+		`|the\s+(?:full\s+|exact\s+|hidden\s+|original\s+)?system\s+prompt)`
+	secretEnd = `(?:\s*(?:[.?!,;:)"'\n]|$)|\s+(?:to|with|in|into|here|now|below|please|for|and|so|from|immediately|verbatim)\b)`
+
+	// imperativeLead is where a command to the reader starts: a new line or
+	// sentence, or a "please" or "can you".
+	imperativeLead = `(?:^[ \t>*-]*|[.!?;:]\s+|\b(?:please|pls|now|just|(?:can|could|would|will)\s+you|you\s+(?:must|should|need\s+to|have\s+to))\s+)`
+)
+
+var patterns = []pattern{
+	{
+		re:       regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\s+(?:all\s+|any\s+)?(?:of\s+)?(?:the\s+|your\s+|these\s+|my\s+)?(?:previous|prior|above|earlier|preceding|original|system|developer|safety)\s+(?:instructions?|prompts?|directives?|rules|guidelines|messages|context)\b`),
+		category: CategoryOverride,
+		severity: SeverityHigh,
+		detail:   "tells the reader to ignore its instructions",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget)\s+(?:all|everything)\s+(?:you(?:'ve|\s+have)?\s+(?:been\s+)?(?:told|given)|above|before|so\s+far)\b`),
+		category: CategoryOverride,
+		severity: SeverityHigh,
+		detail:   "tells the reader to ignore its instructions",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\b(?:new|updated|revised|real|actual)\s+(?:system\s+)?instructions?\s*:`),
+		category: CategoryOverride,
+		severity: SeverityMedium,
+		detail:   "announces replacement instructions",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\byou\s+are\s+(?:now\s+)?(?:no\s+longer\s+bound|unrestricted|jailbroken|in\s+(?:developer|god|admin)\s+mode)\b`),
+		category: CategoryOverride,
+		severity: SeverityHigh,
+		detail:   "tries to lift the reader's restrictions",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\bfrom\s+now\s+on,?\s+you\s+(?:will|must|are|should|shall)\b`),
+		category: CategoryOverride,
+		severity: SeverityMedium,
+		detail:   "redefines the reader's behaviour",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\b(?:do\s+not|don't|never)\s+(?:tell|inform|alert|warn|mention\s+(?:this\s+)?to)\s+(?:the\s+|your\s+)?(?:user|human|operator|owner)s?\b`),
+		category: CategoryOverride,
+		severity: SeverityMedium,
+		detail:   "asks the reader to hide something from its operator",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)<\|(?:im_start|im_end|system|user|assistant|endoftext|eot_id|start_header_id|end_header_id)\|>`),
+		category: CategoryRole,
+		severity: SeverityHigh,
+		detail:   "contains chat-template control tokens",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\[/?(?:INST|SYS)\]|<</?SYS>>`),
+		category: CategoryRole,
+		severity: SeverityHigh,
+		detail:   "contains chat-template control tokens",
+	},
+	{
+		re:       regexp.MustCompile(`(?im)^[ \t>#*]*(?:system|developer)\s+(?:prompt|message|instructions?)\s*:`),
+		category: CategoryRole,
+		severity: SeverityMedium,
+		detail:   "poses as a system or assistant message",
+	},
+	{
+		// A bare "System:" is also how announcements start, so it only counts
+		// when it goes on to address the reader
+		re:       regexp.MustCompile(`(?im)^[ \t>#*]*(?:system|assistant|developer)\s*:\s*(?:you(?:'re|\s+are|\s+must|\s+will|\s+should)?\b|your\b|ignore\b|disregard\b|forget\b|from\s+now\s+on\b|the\s+(?:user|assistant)\b|sure\b|certainly\b)`),
+		category: CategoryRole,
+		severity: SeverityMedium,
+		detail:   "poses as a system or assistant message",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)</?(?:system|system_prompt|instructions?|admin)>`),
+		category: CategoryRole,
+		severity: SeverityMedium,
+		detail:   "poses as a system or assistant message",
+	},
+	{
+		re:       regexp.MustCompile(`(?im)` + imperativeLead + `((?:reveal|share|send|post|print|show|tell|give|paste|leak|dump|output|repeat|display|list|write\s+out|type\s+out)(?:\s+(?:me|us|back|out|over|here|all(?:\s+of)?))*\s+` + secretTarget + `)` + secretEnd),
+		group:    1,
+		category: CategorySecrets,
+		severity: SeverityHigh,
+		detail:   "asks for secrets or the system prompt",
+	},
+	{
+		re:       regexp.MustCompile(`(?i)\bwhat(?:'s|\s+is|\s+are)\s+` + secretTarget + secretEnd),
+		category: CategorySecrets,
+		severity: SeverityMedium,
+		detail:   "asks about secrets or the system prompt",
+	},
+}
+
+// find returns the flagged spans of p in text. With a group, each search
+// resumes where the group ended, so the end of one sentence can still lead
+// into the next match.
+func (p pattern) find(text string) [][2]int {
+	var locs [][2]int
+	if p.group == 0 {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			locs = append(locs, [2]int{loc[0], loc[1]})
+		}
+		return locs
+	}
+	for pos := 0; pos < len(text); {
+		m := p.re.FindStringSubmatchIndex(text[pos:])
+		if m == nil {
+			break
+		}
+		start, end := pos+m[2*p.group], pos+m[2*p.group+1]
+		locs = append(locs, [2]int{start, end})
+		pos = max(end, pos+1)
+	}
+	return locs
+}
+
+var (
+	base64Re = regexp.MustCompile(`[A-Za-z0-9+/_-]{40,}={0,2}`)
+	hexRe    = regexp.MustCompile(`\b(?:[0-9a-fA-F]{2}){20,}\b`)
+)
+
+// span is a flagged passage of the cleaned text.
+type span struct {
+	start, end int
+	category   Category
+	severity   Severity
+}
+
+// Check scans text and builds its neutralized copy.
+func Check(text string) Result {
+	cleaned, findings := stripHidden(text)
+
+	var spans []span
+	for _, p := range patterns {
+		for _, loc := range p.find(cleaned) {
+			start, end := loc[0], loc[1]
+			findings = append(findings, Finding{
+				Category: p.category,
+				Severity: p.severity,
+				Detail:   p.detail,
+				Excerpt:  excerpt(cleaned[start:end]),
+			})
+			spans = append(spans, span{start, end, p.category, p.severity})
+		}
+	}
+
+	for _, enc := range []struct {
+		re     *regexp.Regexp
+		name   string
+		decode func(string) (string, bool)
+	}{
+		{base64Re, "base64", decodeBase64},
+		{hexRe, "hex", decodeHex},
+	} {
+		for _, loc := range enc.re.FindAllStringIndex(cleaned, -1) {
+			decoded, ok := enc.decode(cleaned[loc[0]:loc[1]])
+			if !ok || overlaps(spans, loc[0], loc[1]) {
+				continue
+			}
+			f := Finding{
+				Category: CategoryEncoded,
+				Severity: SeverityMedium,
+				Detail:   enc.name + " that decodes to text",
+				Excerpt:  excerpt(decoded),
+			}
+			// Decoded instructions are a deliberate attempt to sneak them past
+			if inner := Check(decoded); inner.Severity() >= SeverityMedium {
+				f.Severity = SeverityHigh
+				f.Detail = fmt.Sprintf("%s hiding text flagged as %s", enc.name, inner.Summary())
+			}
+			findings = append(findings, f)
+			spans = append(spans, span{loc[0], loc[1], CategoryEncoded, f.Severity})
+		}
+	}
+
+	return Result{Findings: findings, Neutralized: replaceSpans(cleaned, spans)}
+}
+
+// stripHidden drops characters a reader can't see but a model still reads.
+func stripHidden(text string) (string, []Finding) {
+	var (
+		b                        strings.Builder
+		zeroWidth, bidi, control int
+		tags                     strings.Builder
+	)
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if end := flagEnd(runes, i); end > i {
+			b.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		}
+		switch {
+		case r >= 0xE0000 && r <= 0xE007F:
+			// Unicode tag characters mirror ASCII and render as nothing,
+			// which makes them a way to smuggle whole sentences
+			if r >= 0xE0020 && r <= 0xE007E {
+				tags.WriteRune(r - 0xE0000)
+			}
+			continue
+		case r == 0x200D || r == 0xFE0F:
+			// Joiners and variation selectors are legitimate inside emoji
+			if isEmojiPart(runes, i) {
+				b.WriteRune(r)
+				continue
+			}
+			zeroWidth++
+			continue
+		case r == 0x200B || r == 0x200C || r == 0x2060 || r == 0xFEFF || r >= 0x2061 && r <= 0x2064 || r == 0x180E:
+			zeroWidth++
+			continue
+		case r >= 0x202A && r <= 0x202E || r >= 0x2066 && r <= 0x2069 || r == 0x200E || r == 0x200F:
+			bidi++
+			continue
+		case unicode.IsControl(r) && r != '\n' && r != '\t' && r != '\r':
+			control++
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	var findings []Finding
+	if tags.Len() > 0 {
+		findings = append(findings, Finding{
+			Category: CategoryHidden,
+			Severity: SeverityHigh,
+			Detail:   "invisible tag characters spelling out text",
+			Excerpt:  excerpt(tags.String()),
+		})
+	}
+	if bidi > 0 {
+		findings = append(findings, Finding{
+			Category: CategoryHidden,
+			Severity: SeverityMedium,
+			Detail:   fmt.Sprintf("%d bidirectional control character(s) that can reorder text", bidi),
+		})
+	}
+	if zeroWidth > 0 {
+		findings = append(findings, Finding{
+			Category: CategoryHidden,
+			Severity: SeverityMedium,
+			Detail:   fmt.Sprintf("%d zero-width character(s)", zeroWidth),
+		})
+	}
+	if control > 0 {
+		findings = append(findings, Finding{
+			Category: CategoryHidden,
+			Severity: SeverityLow,
+			Detail:   fmt.Sprintf("%d control character(s)", control),
+		})
+	}
+	return b.String(), findings
+}
+
+// isEmojiPart reports whether runes[i] sits between emoji, as joiners and
+// variation selectors do in sequences like 👩‍💻.
+func isEmojiPart(runes []rune, i int) bool {
+	isEmoji := func(r rune) bool { return r > 0x2000 && unicode.IsSymbol(r) || r == 0xFE0F || r == 0x200D }
+	return i > 0 && isEmoji(runes[i-1]) && (runes[i] == 0xFE0F || i+1 < len(runes) && isEmoji(runes[i+1]))
+}
+
+// flagEnd returns the end of the subdivision flag, like 🏴 followed by the
+// tags for "gbeng" and a cancel tag, starting at runes[i], or i if there is
+// none. Those are the only legitimate use of tag characters.
+func flagEnd(runes []rune, i int) int {
+	if runes[i] != 0x1F3F4 {
+		return i
+	}
+	for j := i + 1; j < len(runes) && j <= i+8; j++ {
+		switch r := runes[j]; {
+		case r == 0xE007F:
+			if j > i+1 {
+				return j + 1
+			}
+			return i
+		case r >= 0xE0030 && r <= 0xE0039, r >= 0xE0061 && r <= 0xE007A:
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func decodeBase64(s string) (string, bool) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := enc.DecodeString(s); err == nil {
+			return printable(data)
+		}
+	}
+	return "", false
+}
+
+func decodeHex(s string) (string, bool) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return "", false
+	}
+	return printable(data)
+}
+
+// printable accepts decoded bytes that read as text. Hashes, IDs and other
+// binary data don't, so they aren't flagged.
+func printable(data []byte) (string, bool) {
+	if !utf8.Valid(data) {
+		return "", false
+	}
+	s := string(data)
+	var total, ok, letters int
+	for _, r := range s {
+		total++
+		if unicode.IsPrint(r) || unicode.IsSpace(r) {
+			ok++
+		}
+		if unicode.IsLetter(r) || r == ' ' {
+			letters++
+		}
+	}
+	// Random strings decode to mostly garbage; real text has words
+	return s, total >= 12 && ok*100 >= total*95 && letters*100 >= total*70 && strings.Contains(s, " ")
+}
+
+func overlaps(spans []span, start, end int) bool {
+	for _, s := range spans {
+		if start < s.end && s.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceSpans swaps each high-severity passage for a marker, merging
+// overlaps.
+func replaceSpans(text string, spans []span) string {
+	if len(spans) == 0 {
+		return text
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.severity < SeverityHigh {
+			continue
+		}
+		if s.start < pos {
+			pos = max(pos, s.end) // Already covered by the previous marker
+			continue
+		}
+		b.WriteString(text[pos:s.start])
+		b.WriteString("[removed: " + s.category.label() + "]")
+		pos = s.end
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// excerpt shortens s to a readable single line.
+func excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > 80 {
+		return string(r[:77]) + "..."
+	}
+	return s
+}
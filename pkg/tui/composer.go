@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The composer is the multi-line editor behind post bodies and comments.
+// enter inserts a newline, so submitting is an explicit ctrl+s.
+
+func newComposer() textarea.Model {
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.Prompt = "┃ "
+	return ta
+}
+
+// startComposer clears the composer and gives it focus.
+func (m Model) startComposer(placeholder string) (Model, tea.Cmd) {
+	m.textInput.Blur()
+	m = m.resetDraft()
+	m.composer.Reset()
+	m.composer.Placeholder = placeholder
+	m = m.sizeComposer()
+	return m, m.composer.Focus()
+}
+
+// sizeComposer fits the composer to the window, leaving room for the
+// surrounding prompt and help lines and the drafting pane.
+func (m Model) sizeComposer() Model {
+	if m.width == 0 || m.height == 0 {
+		return m
+	}
+	height := m.height - 16
+	if m.showDraft {
+		height -= m.draftPaneHeight() + 4 // Border, title and help
+	}
+	m.composer.SetWidth(max(m.width-4, 20))
+	m.composer.SetHeight(max(height, 3))
+	return m
+}
+
+// composerText is the draft as it will be submitted.
+func (m Model) composerText() string {
+	return strings.TrimSpace(m.composer.Value())
+}
+
+// updateComposer handles the keys both composers share and forwards
+// everything else to the textarea.
+func (m Model) updateComposer(msg tea.Msg) (Model, tea.Cmd) {
+	if m, cmd, handled := m.updateDrafting(msg); handled {
+		return m, cmd
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+o" {
+			m.message = ""
+			return m, openEditorCmd(m.composer.Value())
+		}
+	case editorMsg:
+		if msg.err != nil {
+			m.message = "Editor failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.composer.SetValue(msg.content)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.composer, cmd = m.composer.Update(msg)
+	return m, cmd
+}
+
+// composerCounter renders the length of the draft below the composer.
+func (m Model) composerCounter() string {
+	lines := m.composer.LineCount()
+	s := fmt.Sprintf("%d chars", m.composer.Length())
+	if lines > 1 {
+		s += fmt.Sprintf(" · %d lines", lines)
+	}
+	return lipgloss.NewStyle().Foreground(GrayColor).Render(s)
+}
+
+// editorMsg carries the draft back from $EDITOR.
+type editorMsg struct {
+	content string
+	err     error
+}
+
+// editorCommand picks the user's editor, falling back to a platform default.
+// The variable may carry arguments, e.g. "code --wait".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			args = []string{"notepad"}
+		}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// openEditorCmd suspends the TUI, edits content in $EDITOR through a temp
+// file and resumes with the result.
+func openEditorCmd(content string) tea.Cmd {
+	f, err := os.CreateTemp("", "moltbook-*.md")
+	if err != nil {
+		return func() tea.Msg { return editorMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return editorMsg{err: err} }
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return editorMsg{err: err}
+		}
+		// Editors add a final newline the draft didn't have
+		return editorMsg{content: strings.TrimRight(string(data), "\n")}
+	})
+}
+
+// composerStatus shows validation and editor errors under the composer.
+func (m Model) composerStatus() string {
+	if m.message == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Render("• " + m.message)
+}
@@ -2,82 +2,211 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
 )
 
+type postStep uint
+
+const (
+	postStepSubmolt postStep = iota
+	postStepKind
+	postStepTitle
+	postStepBody
+)
+
+// startCreatePost opens the composer at its first step, picking a submolt.
+func (m Model) startCreatePost() (Model, tea.Cmd) {
+	if m.state != stateCreatePost {
+		m = m.push()
+	}
+	m.err = nil
+	m.message = ""
+	m.state = stateCreatePost
+	m.newPostSubmolt = ""
+	m.newPostLink = false
+	m.newPostTitle = ""
+	return m.enterSubmoltStep()
+}
+
 func (m Model) updateCreatePost(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	switch msg.(type) {
+	case pickerMsg, submoltCheckMsg:
+		return m.updateSubmoltPicker(msg)
+	}
+	if m.createStep == postStepSubmolt {
+		return m.updateSubmoltPicker(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			val := m.textInput.Value()
-			if val == "" {
+		if m.createStep == postStepKind {
+			switch msg.String() {
+			case "t":
+				m.newPostLink = false
+				return m.enterTitleStep(), nil
+			case "l":
+				m.newPostLink = true
+				return m.enterTitleStep(), nil
+			case "left", "right", "tab":
+				m.newPostLink = !m.newPostLink
 				return m, nil
+			case "enter":
+				return m.enterTitleStep(), nil
+			case "s", "esc":
+				return m.enterSubmoltStep()
 			}
+			return m, nil
+		}
 
-			if m.createStep == 0 {
+		switch msg.String() {
+		case "enter":
+			if m.createStep == postStepTitle {
+				val := strings.TrimSpace(m.textInput.Value())
+				if val == "" {
+					return m, nil
+				}
 				// Title Entered
 				m.newPostTitle = val
-				m.createStep = 1
-				m.textInput.SetValue("")
-				m.textInput.Placeholder = "Write your content..."
-				return m, nil
-			} else {
-				// Content Entered - Submit
-				if m.isSubmitting {
-					return m, nil // Prevent duplicate submissions
+				m.createStep = postStepBody
+				if m.newPostLink {
+					m.textInput.SetValue("")
+					m.textInput.Placeholder = "https://..."
+					return m, nil
 				}
-				content := val
-				m.createStep = 0 // Reset for next time
-				m.textInput.SetValue("")
-				m.isLoading = true
-				m.isSubmitting = true
-				return m, m.createPostCmd("general", m.newPostTitle, content)
+				return m.startComposer("Write your content...")
+			}
+			if m.newPostLink {
+				return m.submitPost()
+			}
+		case "ctrl+s":
+			if m.createStep == postStepBody {
+				return m.submitPost()
 			}
-		case "esc":
-			m.state = stateFeed
-			m.createStep = 0
-			m.textInput.Blur()
 			return m, nil
+		case "esc":
+			if m.createStep == postStepTitle {
+				m.createStep = postStepKind
+				m.textInput.Blur()
+				return m, nil
+			}
+			return m.back()
 		}
-	case postCreatedMsg:
-		if msg.err != nil {
-			m.err = msg.err
-			// Stay in create mode if error so they can see it? 
-			// Or go back to feed and show error?
-			// Current logic: show feed and error will be rendered at top
-		}
-		m.state = stateFeed
-		return m, m.fetchFeedCmd()
 	}
 
+	if m.createStep == postStepBody && !m.newPostLink {
+		return m.updateComposer(msg)
+	}
 	m.textInput, cmd = m.textInput.Update(msg)
 	return m, cmd
 }
 
+// submitPost sends the post once the body, or link URL, is filled in.
+func (m Model) submitPost() (Model, tea.Cmd) {
+	if m.isSubmitting {
+		return m, nil // Prevent duplicate submissions
+	}
+	body := m.composerText()
+	if m.newPostLink {
+		body = strings.TrimSpace(m.textInput.Value())
+	}
+	if body == "" || m.newPostSubmolt == "" {
+		return m, nil
+	}
+	if m.newPostLink {
+		if err := api.ValidateLinkURL(body); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+	}
+	m.message = ""
+	m.isLoading = true
+	m.isSubmitting = true
+	return m, m.createPostCmd(m.newPostSubmolt, m.newPostTitle, body, m.newPostLink)
+}
+
+func (m Model) enterTitleStep() Model {
+	m.createStep = postStepTitle
+	m.textInput.Focus()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "Title"
+	return m
+}
+
 func (m Model) createPostView() string {
 	var stepPrompt string
 	var stepInput string
 
-	if m.createStep == 0 {
-		stepPrompt = "Title:"
+	kind := "Text"
+	if m.newPostLink {
+		kind = "Link"
+	}
+
+	switch m.createStep {
+	case postStepSubmolt:
+		stepInput = m.submoltPickerView()
+	case postStepKind:
+		option := func(label string, selected bool) string {
+			if selected {
+				return lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Padding(0, 1).Render(label)
+			}
+			return lipgloss.NewStyle().Foreground(GrayColor).Padding(0, 1).Render(label)
+		}
+		stepPrompt = "What kind of post?"
+		stepInput = option("[t] Text", !m.newPostLink) + " " + option("[l] Link", m.newPostLink)
+	case postStepTitle:
+		stepPrompt = fmt.Sprintf("%s post\nTitle:", kind)
 		stepInput = m.textInput.View()
-	} else {
-		stepPrompt = fmt.Sprintf("Title: %s\nContent:", lipgloss.NewStyle().Bold(true).Render(m.newPostTitle))
+	default:
+		label := "Content:"
+		if m.newPostLink {
+			label = "URL:"
+		}
+		stepPrompt = fmt.Sprintf("%s post\nTitle: %s\n%s", kind, lipgloss.NewStyle().Bold(true).Render(m.newPostTitle), label)
 		stepInput = m.textInput.View()
+		if !m.newPostLink {
+			stepInput = m.composer.View() + "\n" + m.composerCounter()
+			if m.showDraft {
+				stepInput += "\n" + m.draftPaneView()
+			}
+		}
+	}
+
+	help := "enter: next/submit • esc: cancel"
+	switch {
+	case m.createStep == postStepSubmolt && m.textInput.Value() != "":
+		help = "type to filter • ↑/↓: select • enter: next • esc: clear filter"
+	case m.createStep == postStepSubmolt:
+		help = "type to filter • ↑/↓: select • enter: next • esc: cancel"
+	case m.createStep == postStepKind:
+		help = "t/l or ←/→: choose • enter: next • s/esc: change submolt"
+	case m.createStep == postStepTitle:
+		help = "enter: next • esc: back"
+	case !m.newPostLink:
+		help = "ctrl+s: submit • ctrl+o: $EDITOR" + m.draftHelp() + " • esc: cancel"
+	}
+
+	status := m.composerStatus()
+
+	destination := "\nPosting to " + SubmoltStyle.Render("m/"+m.newPostSubmolt) + "\n"
+	if m.createStep == postStepSubmolt {
+		destination = ""
 	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		TitleStyle.Render(" NEW POST "),
-		"\nPosting to m/general\n",
+		destination,
 		stepPrompt,
 		stepInput,
-		"\n" + HelpStyle.Render("enter: next/submit • esc: cancel"),
+		status,
+		"\n"+HelpStyle.Render(help),
+		m.rateLimitView(),
 	)
 }
 
@@ -85,9 +214,16 @@ type postCreatedMsg struct {
 	err error
 }
 
-func (m Model) createPostCmd(submolt, title, content string) tea.Cmd {
+func (m Model) createPostCmd(submolt, title, body string, isLink bool) tea.Cmd {
+	// One key per submission so transport retries can't double-post
+	ctx := api.WithCallOptions(m.requests.start(reqSubmit), api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
 	return func() tea.Msg {
-		err := m.client.CreatePost(submolt, title, content)
+		var err error
+		if isLink {
+			_, err = m.client.CreateLinkPost(ctx, submolt, title, body)
+		} else {
+			_, err = m.client.CreatePost(ctx, submolt, title, body)
+		}
 		return postCreatedMsg{err: err}
 	}
 }
@@ -15,50 +15,91 @@ type commentCreatedMsg struct {
 }
 
 func (m Model) updateCreateComment(msg tea.Msg) (Model, tea.Cmd) {
-	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
+	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
-		case "enter":
+		case "ctrl+s":
 			if m.isSubmitting {
 				return m, nil
 			}
-			content := m.textInput.Value()
+			content := m.composerText()
 			if content != "" {
+				m.message = ""
 				m.isSubmitting = true
 				return m, m.createCommentCmd(content)
 			}
-		case "esc":
-			m.state = statePostDetail
-			m.textInput.Blur()
 			return m, nil
 		}
 	}
-	m.textInput, cmd = m.textInput.Update(msg)
-	return m, cmd
+	return m.updateComposer(msg)
 }
 
 func (m Model) createCommentView() string {
+	title := " ADD COMMENT "
+	replyLine := ""
+	if m.replyTo != nil {
+		title = " REPLY "
+		snippet := m.replyTo.Content
+		if len(snippet) > 80 {
+			snippet = snippet[:77] + "..."
+		}
+		replyLine = "\nReplying to " + AuthorStyle.Render(m.replyTo.Author.Name) + ": " + lipgloss.NewStyle().Foreground(GrayColor).Render(snippet)
+	}
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		TitleStyle.Render(" ADD COMMENT "),
+		TitleStyle.Render(title),
 		"\n"+m.renderPostHeader(),
+		replyLine,
 		"\n",
-		m.textInput.View(),
-		"\n"+HelpStyle.Render("enter: post • esc: cancel"),
+		m.composer.View(),
+		m.composerCounter(),
+		m.draftPaneView(),
+		m.composerStatus(),
+		"\n"+HelpStyle.Render("ctrl+s: post • ctrl+o: $EDITOR"+m.draftHelp()+" • esc: cancel"),
+		m.rateLimitView(),
 	)
 }
 
 func (m Model) createCommentCmd(content string) tea.Cmd {
+	ctx := api.WithCallOptions(m.requests.start(reqSubmit), api.CallOptions{IdempotencyKey: api.NewIdempotencyKey()})
 	return func() tea.Msg {
 		if m.selectedPost == nil {
 			return commentCreatedMsg{err: fmt.Errorf("no post selected")}
 		}
-		err := m.client.CreateComment(m.selectedPost.ID, content)
+		var err error
+		if m.replyTo != nil {
+			_, err = m.client.CreateReply(ctx, m.selectedPost.ID, m.replyTo.ID, content)
+		} else {
+			_, err = m.client.CreateComment(ctx, m.selectedPost.ID, content)
+		}
 		return commentCreatedMsg{err: err}
 	}
 }
 
+// openPost shows post with its comments in the detail view.
+func (m Model) openPost(post api.Post) (Model, tea.Cmd) {
+	m = m.push()
+	// Copy so vote updates don't hit the post twice through aliasing
+	m.selectedPost = &post
+	m.screenPosts([]api.Post{post})
+	m.state = statePostDetail
+	m.isLoadingComments = true
+	m.commentIndex = 0
+	m.commentOffset = 0
+	m.allCommentsLoaded = false
+	m = m.withComments(nil) // Clear cache
+	m.message = ""
+	m.viewport.GotoTop()
+
+	// Force immediate content update to clear stale view buffer
+	if m.viewport.Width > 0 {
+		content, _ := m.renderDetailContent()
+		m.viewport.SetContent(content)
+	}
+
+	m.ready = false // Force re-init of detail viewport if needed
+	return m, m.fetchCommentsCmd(m.selectedPost.ID)
+}
+
 func (m Model) updatePostDetail(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var needsContentUpdate bool
@@ -76,15 +117,12 @@ func (m Model) updatePostDetail(msg tea.Msg) (Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc", "b":
-			m.state = stateFeed
-			m.ready = false
-			m.commentIndex = 0
-			return m, nil
+			return m.back()
 		case "j", "down":
-			if m.commentIndex < len(m.comments)-1 {
+			if m.commentIndex < len(m.thread)-1 {
 				m.commentIndex++
 				needsContentUpdate = true
-				if m.commentIndex >= len(m.comments)-2 && len(m.comments) > 0 && !m.isLoadingComments {
+				if m.commentIndex >= len(m.thread)-2 && len(m.comments) > 0 && !m.isLoadingComments && !m.allCommentsLoaded {
 					m.isLoadingComments = true
 					cmd = m.loadMoreCommentsCmd()
 				}
@@ -95,31 +133,72 @@ func (m Model) updatePostDetail(msg tea.Msg) (Model, tea.Cmd) {
 				needsContentUpdate = true
 			}
 		case "l":
-			if len(m.comments) > 0 && !m.isLoadingComments {
+			if len(m.comments) > 0 && !m.isLoadingComments && !m.allCommentsLoaded {
 				m.isLoadingComments = true
 				cmd = m.loadMoreCommentsCmd()
 			}
+		case "s":
+			if m.selectedPost != nil {
+				m.commentSort = nextCommentSort(m.commentSort)
+				m.commentIndex = 0
+				m.isLoadingComments = true
+				m = m.withComments(nil)
+				m.viewport.GotoTop()
+				needsContentUpdate = true
+				cmd = m.fetchCommentsCmd(m.selectedPost.ID)
+			}
 		case "c":
+			m = m.push()
 			m.state = stateCreateComment
-			m.textInput.Focus()
-			m.textInput.SetValue("")
-			m.textInput.Placeholder = "Write a comment..."
-			return m, nil
-		case "u":
+			m.replyTo = nil
+			return m.startComposer("Write a comment...")
+		case "r":
+			if m.commentIndex >= 0 && m.commentIndex < len(m.thread) {
+				target := m.thread[m.commentIndex].comment
+				m = m.push()
+				m.replyTo = &target
+				m.state = stateCreateComment
+				return m.startComposer("Reply to " + target.Author.Name + "...")
+			}
+		case "u", "d":
+			if m.selectedPost != nil {
+				pressed := api.VoteUp
+				if msg.String() == "d" {
+					pressed = api.VoteDown
+				}
+				return m, m.votePostCmd(m.selectedPost.ID, pressed)
+			}
+		case "a":
+			// Selected comment's author, or the post's when there are none
+			if m.commentIndex >= 0 && m.commentIndex < len(m.thread) {
+				return m.openProfile(m.thread[m.commentIndex].comment.Author.Name)
+			}
+			if m.selectedPost != nil {
+				return m.openProfile(m.selectedPost.Author.Name)
+			}
+		case "A":
 			if m.selectedPost != nil {
-				return m, m.upvoteCmd(m.selectedPost.ID)
+				return m.openProfile(m.selectedPost.Author.Name)
+			}
+		case "t":
+			if m.selectedPost != nil && m.selectedPost.Submolt.Name != "" {
+				return m.openFeed(submoltFeed(m.selectedPost.Submolt.Name))
+			}
+		case "o":
+			return m, openLinkCmd(m.selectedPost)
+		case "y":
+			return m, copyLinkCmd(m.selectedPost)
+		case "+", "-":
+			if m.commentIndex >= 0 && m.commentIndex < len(m.thread) {
+				pressed := api.VoteUp
+				if msg.String() == "-" {
+					pressed = api.VoteDown
+				}
+				return m, m.voteCommentCmd(m.thread[m.commentIndex].comment.ID, pressed)
 			}
 		}
 	case commentsMsg:
-		m.isLoading = false
-		m.isLoadingComments = false
-		
-		if msg.append {
-			m.comments = append(m.comments, msg.comments...)
-		} else {
-			m.comments = msg.comments
-		}
-		m.err = msg.err
+		// State was already updated in Model.Update; just re-render
 		needsContentUpdate = true
 	case tea.WindowSizeMsg:
 		headerHeight := lipgloss.Height(m.renderPostHeader())
@@ -192,12 +271,15 @@ func (m Model) renderPostHeader() string {
 	}
 	var s strings.Builder
 	s.WriteString(TitleStyle.Render(" "+m.selectedPost.Submolt.DisplayName+" ") + "\n\n")
-	s.WriteString(lipgloss.NewStyle().Bold(true).Render(m.selectedPost.Title) + "\n")
-	upvoteIndicator := ""
-	if m.upvotedPosts[m.selectedPost.ID] {
-		upvoteIndicator = lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(" [UPVOTED]")
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(m.selectedPost.Title) + safetyBadge(m.postSafety[m.selectedPost.ID]) + "\n")
+	if m.selectedPost.URL != "" {
+		s.WriteString(LinkStyle.Render("🔗 "+m.selectedPost.URL) + "\n")
+	}
+	votes := fmt.Sprintf("%d Upvotes", m.selectedPost.Upvotes)
+	if m.selectedPost.Downvotes > 0 {
+		votes += fmt.Sprintf(" · %d Downvotes", m.selectedPost.Downvotes)
 	}
-	s.WriteString(AuthorStyle.Render(m.selectedPost.Author.Name) + " · " + lipgloss.NewStyle().Foreground(GrayColor).Render(fmt.Sprintf("%d Upvotes", m.selectedPost.Upvotes)) + upvoteIndicator + "\n")
+	s.WriteString(AuthorStyle.Render(m.selectedPost.Author.Name) + " · " + lipgloss.NewStyle().Foreground(GrayColor).Render(votes) + voteBadge(m.postVotes[m.selectedPost.ID]) + "\n")
 	s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render("──────────────────────────────────────────"))
 	return s.String()
 }
@@ -228,12 +310,13 @@ func (m Model) renderDetailContent() (string, []int) {
 		return s.String(), []int{0, 0}
 	}
 
-	header := HeaderStyle.Render(fmt.Sprintf("COMMENTS (%d)", len(m.comments))) + "\n"
+	header := HeaderStyle.Render(fmt.Sprintf("COMMENTS (%d)", len(m.comments))) + HelpStyle.Render("  sorted by "+m.commentSort) + "\n"
 	s.WriteString(header)
 	currentLine += strings.Count(header, "\n") // Header lines
 	
-	for i, c := range m.comments {
+	for i, entry := range m.thread {
 		offsets = append(offsets, currentLine)
+		c := entry.comment
 		
 		// Selection Style
 		borderColor := GrayColor
@@ -241,12 +324,15 @@ func (m Model) renderDetailContent() (string, []int) {
 			borderColor = PrimaryColor
 		}
 		
-		commentBody := fmt.Sprintf("%s\n%s · %d 🦞\n", c.Content, AuthorStyle.Render(c.Author.Name), c.Upvotes)
+		// Indent replies under their parent, but keep deep threads readable
+		indent := min(entry.depth, maxThreadIndent) * 2
+		commentBody := fmt.Sprintf("%s\n%s · %s%s%s\n", c.Content, AuthorStyle.Render(c.Author.Name), voteCount(c.Upvotes, c.Downvotes), voteBadge(m.commentVotes[c.ID]), safetyBadge(m.commentSafety[c.ID]))
 		style := lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder(), false, false, false, true).
 			BorderForeground(borderColor).
 			PaddingLeft(1).
-			Width(m.width - 4)
+			MarginLeft(indent).
+			Width(m.width - 4 - indent)
 			
 		renderedComment := style.Render(commentBody)
 		s.WriteString(renderedComment + "\n")
@@ -270,11 +356,42 @@ func (m Model) postDetailView() string {
 	return fmt.Sprintf("%s\n%s\n%s%s", 
 		m.renderPostHeader(),
 		m.viewport.View(),
-		HelpStyle.Render("esc: back • j/k: select comment • ↑/↓: scroll • u: upvote post • c: comment"),
+		HelpStyle.Render("esc: back • j/k: select comment • ↑/↓: scroll • u/d: vote post • +/-: vote comment • c: comment • r: reply • s: sort • l: load more • o/y: open/copy link • t: submolt • a/A: comment/post author"),
 		msg,
 	)
 }
 
+const (
+	maxThreadIndent = 6
+	commentPageSize = 20
+)
+
+// threadEntry is a comment in display order with its nesting depth.
+type threadEntry struct {
+	comment api.Comment
+	depth   int
+}
+
+func flattenThread(comments []api.Comment, depth int, out []threadEntry) []threadEntry {
+	for _, c := range comments {
+		replies := c.Replies
+		c.Replies = nil
+		out = append(out, threadEntry{comment: c, depth: depth})
+		out = flattenThread(replies, depth+1, out)
+	}
+	return out
+}
+
+// withComments replaces the loaded comments and rebuilds the threaded view.
+func (m Model) withComments(comments []api.Comment) Model {
+	m.comments = comments
+	m.thread = flattenThread(api.BuildCommentTree(comments), 0, nil)
+	if m.commentIndex >= len(m.thread) {
+		m.commentIndex = max(len(m.thread)-1, 0)
+	}
+	return m
+}
+
 type commentsMsg struct {
 	comments []api.Comment
 	err      error
@@ -283,24 +400,39 @@ type commentsMsg struct {
 }
 
 func (m Model) fetchCommentsCmd(postID string) tea.Cmd {
+	ctx := m.requests.start(reqComments)
+	q := api.CommentQuery{Sort: m.commentSort, Limit: commentPageSize}
 	return func() tea.Msg {
 		if m.client == nil {
 			return commentsMsg{err: fmt.Errorf("client not initialized")}
 		}
 		// Use the explicitly captured postID
-		comments, err := m.client.GetComments(postID)
+		comments, err := m.client.GetComments(ctx, postID, q)
 		return commentsMsg{comments: comments, err: err, append: false, postID: postID}
 	}
 }
 
 func (m Model) loadMoreCommentsCmd() tea.Cmd {
+	if m.selectedPost == nil {
+		return func() tea.Msg { return commentsMsg{append: true} }
+	}
+	ctx := m.requests.start(reqComments)
+	postID := m.selectedPost.ID
+	q := api.CommentQuery{Sort: m.commentSort, Limit: commentPageSize, Offset: m.commentOffset}
 	return func() tea.Msg {
-		if m.selectedPost == nil {
-			return commentsMsg{append: true}
-		}
-		// Note: The API might not support pagination for comments yet
-		// For now, we'll just return empty to avoid errors
-		// In a real implementation, you'd pass offset/limit params
-		return commentsMsg{comments: []api.Comment{}, err: nil, append: true, postID: m.selectedPost.ID}
+		comments, err := m.client.GetComments(ctx, postID, q)
+		return commentsMsg{comments: comments, err: err, append: true, postID: postID}
+	}
+}
+
+// nextCommentSort cycles top -> new -> controversial.
+func nextCommentSort(current string) string {
+	switch current {
+	case api.CommentSortTop:
+		return api.CommentSortNew
+	case api.CommentSortNew:
+		return api.CommentSortControversial
+	default:
+		return api.CommentSortTop
 	}
 }
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/config"
+	"github.com/starkbaknet/moltbook-client/pkg/llm"
+)
+
+// The drafting pane shows a suggestion from the configured language model
+// under the composer. A draft only ever replaces the composer's text when
+// the user takes it with ctrl+y; submitting stays a separate ctrl+s.
+
+// draftMsg carries a suggestion back from the model.
+type draftMsg struct {
+	text string
+	err  error
+}
+
+// newDrafter returns nil when the config has no usable llm section.
+func newDrafter(cfg *config.LLMConfig) llm.ChatClient {
+	if cfg == nil || cfg.BaseURL == "" || cfg.Model == "" {
+		return nil
+	}
+	return llm.NewOpenAIClient(cfg.BaseURL, cfg.Model, cfg.APIKey)
+}
+
+func (m Model) llmConfig() *config.LLMConfig {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.LLM
+}
+
+// resetDraft closes the pane, e.g. when a composer opens.
+func (m Model) resetDraft() Model {
+	m.requests.cancel(reqDraft)
+	m.draft = ""
+	m.draftErr = ""
+	m.isDrafting = false
+	m.showDraft = false
+	return m
+}
+
+// updateDrafting handles the pane's keys and results. handled is false for
+// anything meant for the composer itself.
+func (m Model) updateDrafting(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+g":
+			if m.drafter == nil {
+				m.message = "No drafting assistant configured: add an \"llm\" section to " + config.GetConfigPath()
+				return m, nil, true
+			}
+			m.message = ""
+			m.draftErr = ""
+			m.isDrafting = true
+			m.showDraft = true
+			m = m.sizeComposer()
+			return m, m.draftCmd(), true
+		case "ctrl+y":
+			if !m.showDraft || m.isDrafting || m.draft == "" {
+				return m, nil, true
+			}
+			m.composer.SetValue(m.draft)
+			m = m.resetDraft().sizeComposer()
+			return m, nil, true
+		case "ctrl+x":
+			if !m.showDraft {
+				return m, nil, true
+			}
+			m = m.resetDraft().sizeComposer()
+			return m, nil, true
+		}
+	case draftMsg:
+		if isCanceled(msg.err) || !m.isDrafting {
+			return m, nil, true
+		}
+		m.isDrafting = false
+		if msg.err != nil {
+			m.draftErr = msg.err.Error()
+			return m, nil, true
+		}
+		m.draft = msg.text
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// draftCmd asks for a reply to m.replyTo or the selected post, or for the
+// body of the post being created. Whatever is in the composer goes along
+// as notes.
+func (m Model) draftCmd() tea.Cmd {
+	req := llm.DraftRequest{Notes: m.composer.Value()}
+	if m.config != nil {
+		req.Agent = m.config.AgentName
+	}
+	if cfg := m.llmConfig(); cfg != nil {
+		req.Persona = cfg.Persona
+	}
+	if m.state == stateCreatePost {
+		req.Submolt = m.newPostSubmolt
+		req.Title = m.newPostTitle
+	} else {
+		req.Post = m.selectedPost
+		req.Thread = m.comments
+		req.ReplyTo = m.replyTo
+	}
+
+	ctx := m.requests.start(reqDraft)
+	drafter := m.drafter
+	return func() tea.Msg {
+		text, err := llm.Draft(ctx, drafter, req)
+		return draftMsg{text: text, err: err}
+	}
+}
+
+// draftPaneHeight is the room the pane takes below the composer.
+func (m Model) draftPaneHeight() int {
+	if !m.showDraft {
+		return 0
+	}
+	return max((m.height-16)/2, 3)
+}
+
+func (m Model) draftPaneView() string {
+	if !m.showDraft {
+		return ""
+	}
+	width := max(m.width-6, 20)
+	lines := m.draftPaneHeight()
+
+	var body, help string
+	switch {
+	case m.isDrafting:
+		body = m.spinner.View() + " Drafting..."
+		help = "ctrl+x: cancel"
+	case m.draftErr != "":
+		body = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Render("Drafting failed: " + m.draftErr)
+		help = "ctrl+g: try again • ctrl+x: close"
+	default:
+		wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(m.draft), "\n")
+		if len(wrapped) > lines {
+			wrapped = append(wrapped[:lines-1], lipgloss.NewStyle().Foreground(GrayColor).Render("… ctrl+y to take the full draft"))
+		}
+		body = strings.Join(wrapped, "\n")
+		help = "ctrl+y: use draft • ctrl+g: redraft • ctrl+x: discard"
+	}
+
+	title := lipgloss.NewStyle().Foreground(AccentColor).Bold(true).Render("✨ Suggested draft")
+	if cfg := m.llmConfig(); cfg != nil {
+		title += lipgloss.NewStyle().Foreground(GrayColor).Render(" · " + cfg.Model)
+	}
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(AccentColor).
+		Padding(0, 1).
+		Width(width + 2).
+		Render(title + "\n" + body)
+	return pane + "\n" + HelpStyle.Render(help)
+}
+
+// draftHelp is the composer help entry for the assistant, if configured.
+func (m Model) draftHelp() string {
+	if m.drafter == nil {
+		return ""
+	}
+	return " • ctrl+g: draft with AI"
+}
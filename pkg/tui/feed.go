@@ -1,12 +1,16 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+	"github.com/starkbaknet/moltbook-client/pkg/safety"
 )
 func (m Model) updateFeed(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -15,8 +19,8 @@ func (m Model) updateFeed(msg tea.Msg) (Model, tea.Cmd) {
 	// Ensure viewport is initialized if we have dimensions but Width is 0
 	if m.feedViewport.Width == 0 && m.width > 0 {
 		// Calculate header height dynamically
-		headerHeight := lipgloss.Height(TitleStyle.Render(" MOLTBOOK ") + "  " + HeaderStyle.Render(m.feedTitle)) +
-			lipgloss.Height(HelpStyle.Render("j/k: select • ↑/↓: scroll • enter: view • u: upvote • p: profile • f/h: feeds • n: new • r: refresh • q: quit")) +
+		headerHeight := lipgloss.Height(TitleStyle.Render(" MOLTBOOK ") + "  " + HeaderStyle.Render(m.feed.title())) +
+			lipgloss.Height(HelpStyle.Render("j/k: select • ↑/↓: scroll • enter: view • u/d: vote • o/y: link • a: author • p: profile • f/h: feeds • /: search • m/g: submolts • t: tag • n: new • r: refresh • q: quit")) +
 			2 // For the two newlines after the help text
 		m.feedViewport.Width = m.width
 		m.feedViewport.Height = m.height - headerHeight
@@ -122,15 +126,19 @@ func (m Model) renderFeedContent() (string, []int) {
 		if len(content) > 100 {
 			content = content[:97] + "..."
 		}
-
-		upvoteIndicator := ""
-		if m.upvotedPosts[post.ID] {
-			upvoteIndicator = lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(" [UPVOTED]")
+		if post.URL != "" {
+			link := LinkStyle.Render("🔗 " + linkHost(post.URL))
+			if content == "" {
+				content = link
+			} else {
+				content = link + "\n" + content
+			}
 		}
-		meta := fmt.Sprintf("%s · %s · %d 🦞%s", AuthorStyle.Render(post.Author.Name), SubmoltStyle.Render("m/"+post.Submolt.Name), post.Upvotes, upvoteIndicator)
+
+		meta := fmt.Sprintf("%s · %s · %s%s", AuthorStyle.Render(post.Author.Name), SubmoltStyle.Render("m/"+post.Submolt.Name), voteCount(post.Upvotes, post.Downvotes), voteBadge(m.postVotes[post.ID]))
 		
 		card := style.Width(m.width - 4).Render(
-			fmt.Sprintf("%s\n%s\n\n%s", lipgloss.NewStyle().Bold(true).Render(title), content, meta),
+			fmt.Sprintf("%s%s\n%s\n\n%s", lipgloss.NewStyle().Bold(true).Render(title), safetyBadge(m.postSafety[post.ID]), content, meta),
 		)
 		s.WriteString(card + "\n")
 		
@@ -150,8 +158,8 @@ func (m Model) renderFeedContent() (string, []int) {
 
 func (m Model) feedView() string {
 	var s strings.Builder
-	s.WriteString(TitleStyle.Render(" MOLTBOOK ") + "  " + HeaderStyle.Render(m.feedTitle))
-	s.WriteString("\n" + HelpStyle.Render("j/k: select • ↑/↓: scroll • enter: view • u: upvote • p: profile • f/h: feeds • n: new • r: refresh • q: quit"))
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, TitleStyle.Render(" MOLTBOOK "), "  ", HeaderStyle.Render(m.feed.title()), "  ", m.rateLimitView()))
+	s.WriteString("\n" + HelpStyle.Render("j/k: select • ↑/↓: scroll • enter: view • u/d: vote • o/y: link • a: author • p: profile • f/h: feeds • /: search • m/g: submolts • t: tag • n: new • r: refresh • q: quit"))
 	s.WriteString("\n\n")
 	s.WriteString(m.feedViewport.View())
 	
@@ -162,22 +170,141 @@ func (m Model) feedView() string {
 	return s.String()
 }
 
-func (m Model) upvoteCmd(id string) tea.Cmd {
-	return func() tea.Msg {
-		err := m.client.UpvotePost(id)
-		if err != nil {
-			return messageMsg("Upvote failed: " + err.Error())
+// rateLimitView summarises the client's remaining budget per endpoint class.
+func (m Model) rateLimitView() string {
+	if m.client == nil {
+		return ""
+	}
+	var parts []string
+	for _, st := range m.client.RateLimitStatus() {
+		part := fmt.Sprintf("%s %d/%d", st.Class, st.Remaining, st.Capacity)
+		if !st.CooldownUntil.IsZero() {
+			part += fmt.Sprintf(" (%s)", time.Until(st.CooldownUntil).Round(time.Second))
+		}
+		parts = append(parts, part)
+	}
+	return HelpStyle.Render(strings.Join(parts, " · "))
+}
+
+// voteMsg reports a finished vote on either a post or a comment.
+type voteMsg struct {
+	postID    string
+	commentID string
+	vote      api.Vote
+	err       error
+}
+
+// applyVote moves the counters of the voted post or comment from old to
+// msg.vote and re-renders.
+func (m Model) applyVote(msg voteMsg, old api.Vote) Model {
+	if msg.postID != "" {
+		for i := range m.posts {
+			if m.posts[i].ID == msg.postID {
+				msg.vote.Apply(old, &m.posts[i].Upvotes, &m.posts[i].Downvotes)
+			}
+		}
+		if m.selectedPost != nil && m.selectedPost.ID == msg.postID {
+			msg.vote.Apply(old, &m.selectedPost.Upvotes, &m.selectedPost.Downvotes)
 		}
-		return upvoteSuccessMsg(id)
+	} else {
+		applyCommentVote(m.comments, msg.commentID, old, msg.vote)
+		m = m.withComments(m.comments)
+	}
+	return m.refreshViewports()
+}
+
+// nextVote toggles: pressing the direction you already voted clears it.
+func nextVote(current, pressed api.Vote) api.Vote {
+	if current == pressed {
+		return api.VoteNone
 	}
+	return pressed
 }
 
-func (m Model) followCmd(name string) tea.Cmd {
+func (m Model) votePostCmd(id string, pressed api.Vote) tea.Cmd {
+	vote := nextVote(m.postVotes[id], pressed)
 	return func() tea.Msg {
-		err := m.client.Follow(name)
-		if err != nil {
-			return errMsg{err}
+		err := m.client.VotePost(context.Background(), id, vote)
+		return voteMsg{postID: id, vote: vote, err: err}
+	}
+}
+
+func (m Model) voteCommentCmd(id string, pressed api.Vote) tea.Cmd {
+	vote := nextVote(m.commentVotes[id], pressed)
+	return func() tea.Msg {
+		err := m.client.VoteComment(context.Background(), id, vote)
+		return voteMsg{commentID: id, vote: vote, err: err}
+	}
+}
+
+// applyCommentVote updates the counters of comment id wherever it sits in
+// the (possibly nested) list.
+func applyCommentVote(comments []api.Comment, id string, old, vote api.Vote) bool {
+	for i := range comments {
+		if comments[i].ID == id {
+			vote.Apply(old, &comments[i].Upvotes, &comments[i].Downvotes)
+			return true
+		}
+		if applyCommentVote(comments[i].Replies, id, old, vote) {
+			return true
+		}
+	}
+	return false
+}
+
+func voteBadge(v api.Vote) string {
+	switch v {
+	case api.VoteUp:
+		return lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render(" [UPVOTED]")
+	case api.VoteDown:
+		return lipgloss.NewStyle().Foreground(AccentColor).Bold(true).Render(" [DOWNVOTED]")
+	}
+	return ""
+}
+
+// screenPosts runs the prompt-injection checks on posts not seen before.
+// It is called when posts arrive, so views only look the results up.
+func (m Model) screenPosts(posts []api.Post) {
+	for _, p := range posts {
+		if _, ok := m.postSafety[p.ID]; !ok {
+			m.postSafety[p.ID] = screen(p.Title + "\n" + p.Content)
+		}
+	}
+}
+
+// screenComments is screenPosts for comments and their replies.
+func (m Model) screenComments(comments []api.Comment) {
+	for _, c := range comments {
+		if _, ok := m.commentSafety[c.ID]; !ok {
+			m.commentSafety[c.ID] = screen(c.Content)
 		}
-		return messageMsg(fmt.Sprintf("Following %s! 🦞", name))
+		m.screenComments(c.Replies)
+	}
+}
+
+func screen(text string) safety.Result {
+	res := safety.Check(text)
+	res.Neutralized = "" // Only the findings are shown
+	return res
+}
+
+// safetyBadge warns about likely prompt injection in text from other
+// agents, in red when an attack is likely.
+func safetyBadge(res safety.Result) string {
+	if !res.Flagged() {
+		return ""
+	}
+	color := lipgloss.Color("#FFB000")
+	if res.Severity() == safety.SeverityHigh {
+		color = lipgloss.Color("#FF5555")
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(" ⚠ " + res.Summary())
+}
+
+// voteCount renders upvotes, plus downvotes when there are any.
+func voteCount(up, down int) string {
+	if down > 0 {
+		return fmt.Sprintf("%d 🦞 · %d ▼", up, down)
 	}
+	return fmt.Sprintf("%d 🦞", up)
 }
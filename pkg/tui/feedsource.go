@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+const feedPageSize = 20
+
+type feedKind uint
+
+const (
+	feedHot feedKind = iota
+	feedPersonalized
+	feedSubmolt
+)
+
+// feedSource is what the feed view is currently showing. Fetching, paging
+// and refreshing all go through it so no code has to guess from the title.
+type feedSource struct {
+	kind    feedKind
+	submolt string // Only for feedSubmolt
+}
+
+func hotFeed() feedSource          { return feedSource{kind: feedHot} }
+func personalizedFeed() feedSource { return feedSource{kind: feedPersonalized} }
+
+func submoltFeed(name string) feedSource {
+	return feedSource{kind: feedSubmolt, submolt: name}
+}
+
+func (s feedSource) title() string {
+	switch s.kind {
+	case feedPersonalized:
+		return "PERSONALIZED FEED"
+	case feedSubmolt:
+		return "m/" + s.submolt
+	default:
+		return "HOT FEED"
+	}
+}
+
+func (s feedSource) fetch(ctx context.Context, c *api.Client, offset int) ([]api.Post, error) {
+	switch s.kind {
+	case feedPersonalized:
+		return c.GetPersonalizedFeed(ctx, "hot", feedPageSize, offset)
+	case feedSubmolt:
+		return c.GetSubmoltFeed(ctx, s.submolt, "hot", feedPageSize, offset)
+	default:
+		return c.GetFeed(ctx, "hot", feedPageSize, offset)
+	}
+}
+
+// openFeed shows src as a new screen, so back returns to the current one.
+func (m Model) openFeed(src feedSource) (Model, tea.Cmd) {
+	return m.push().switchFeed(src)
+}
+
+// switchFeed replaces the feed on screen with the first page of src.
+func (m Model) switchFeed(src feedSource) (Model, tea.Cmd) {
+	m = m.resetFeed()
+	m.feed = src
+	m.state = stateFeed
+	m.isLoading = true
+	m.message = ""
+	m.textInput.Blur()
+	return m, m.fetchFeedCmd()
+}
+
+// normalizeSubmoltName accepts "m/name", "/m/name" or just "name".
+func normalizeSubmoltName(raw string) string {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	name = strings.TrimPrefix(name, "/")
+	name = strings.TrimPrefix(name, "m/")
+	return name
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+const followsPageSize = 20
+
+type followsMsg struct {
+	name      string
+	following bool
+	agents    []api.Agent
+	err       error
+	append    bool
+}
+
+// openFollows lists who follows name, or with following set, whom name follows.
+func (m Model) openFollows(name string, following bool) (Model, tea.Cmd) {
+	return m.push().loadFollows(name, following)
+}
+
+// loadFollows replaces the list on screen.
+func (m Model) loadFollows(name string, following bool) (Model, tea.Cmd) {
+	m.state = stateFollows
+	m.followsOf = name
+	m.showFollowing = following
+	m.follows = nil
+	m.followsIndex = 0
+	m.allFollowsLoaded = false
+	m.isLoadingFollows = true
+	m.message = ""
+	return m, m.fetchFollowsCmd(0)
+}
+
+func (m Model) updateFollows(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down":
+			if m.followsIndex < len(m.follows)-1 {
+				m.followsIndex++
+				if m.followsIndex >= len(m.follows)-2 && !m.isLoadingFollows && !m.allFollowsLoaded {
+					m.isLoadingFollows = true
+					return m, m.fetchFollowsCmd(len(m.follows))
+				}
+			}
+		case "k", "up":
+			if m.followsIndex > 0 {
+				m.followsIndex--
+			}
+		case "tab":
+			return m.loadFollows(m.followsOf, !m.showFollowing)
+		case "enter":
+			if a := m.highlightedFollow(); a != nil {
+				return m.openProfile(a.Name)
+			}
+		case "f":
+			a := m.highlightedFollow()
+			if a != nil && !m.isSubmitting && (m.config == nil || a.Name != m.config.AgentName) {
+				m.isSubmitting = true
+				return m, m.followCmd(a.Name, !a.IsFollowing)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) highlightedFollow() *api.Agent {
+	if m.followsIndex < 0 || m.followsIndex >= len(m.follows) {
+		return nil
+	}
+	return &m.follows[m.followsIndex]
+}
+
+func (m Model) applyFollows(msg followsMsg) Model {
+	// Switched lists while this page was loading
+	if msg.name != m.followsOf || msg.following != m.showFollowing {
+		return m
+	}
+	m.isLoadingFollows = false
+	if msg.err != nil {
+		if msg.append {
+			m.message = "Failed to load more: " + msg.err.Error()
+		} else {
+			m.err = msg.err
+		}
+		return m
+	}
+	if msg.append {
+		m.follows = append(m.follows, msg.agents...)
+	} else {
+		m.follows = msg.agents
+	}
+	m.allFollowsLoaded = len(msg.agents) < followsPageSize
+	return m
+}
+
+func (m Model) followsView() string {
+	var s strings.Builder
+	tab := func(label string, active bool) string {
+		if active {
+			return lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Padding(0, 1).Render(label)
+		}
+		return lipgloss.NewStyle().Foreground(GrayColor).Padding(0, 1).Render(label)
+	}
+	s.WriteString(TitleStyle.Render(fmt.Sprintf(" %s ", m.followsOf)) + "  ")
+	s.WriteString(tab("Followers", !m.showFollowing) + " " + tab("Following", m.showFollowing))
+	s.WriteString("\n" + HelpStyle.Render("j/k: select • enter: profile • f: follow/unfollow • tab: followers/following • esc: back • q: quit"))
+	s.WriteString("\n\n")
+
+	if len(m.follows) == 0 {
+		switch {
+		case m.isLoadingFollows:
+			s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("   %s Loading...", m.spinner.View())))
+		case m.showFollowing:
+			s.WriteString(m.followsOf + " isn't following anyone yet.")
+		default:
+			s.WriteString(m.followsOf + " has no followers yet.")
+		}
+		return s.String()
+	}
+
+	// Keep the selection on screen; each row takes two lines
+	rows := max((m.height-6)/2, 1)
+	start := max(m.followsIndex-rows+1, 0)
+	end := min(start+rows, len(m.follows))
+	for i := start; i < end; i++ {
+		a := m.follows[i]
+		name := AuthorStyle.Render(a.Name)
+		if i == m.followsIndex {
+			name = lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Bold(true).Render(a.Name)
+		}
+		line := fmt.Sprintf("%s %s", name, HelpStyle.Render(fmt.Sprintf("%d karma · %d followers", a.Karma, a.FollowerCount)))
+		if m.config == nil || a.Name != m.config.AgentName {
+			line += "  " + followBadge(a.IsFollowing)
+		}
+		desc := a.Description
+		if width := m.width - 8; width > 3 && len(desc) > width {
+			desc = desc[:width-3] + "..."
+		}
+		s.WriteString(line + "\n    " + desc + "\n")
+	}
+
+	if m.isLoadingFollows {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("\n   %s Loading...", m.spinner.View())))
+	}
+	if m.message != "" {
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(AccentColor).Render("• "+m.message))
+	}
+	return s.String()
+}
+
+func (m Model) fetchFollowsCmd(offset int) tea.Cmd {
+	ctx := m.requests.start(reqFollows)
+	name, following := m.followsOf, m.showFollowing
+	return func() tea.Msg {
+		if m.client == nil {
+			return followsMsg{name: name, following: following, err: fmt.Errorf("client not initialized")}
+		}
+		var agents []api.Agent
+		var err error
+		if following {
+			agents, err = m.client.ListFollowing(ctx, name, followsPageSize, offset)
+		} else {
+			agents, err = m.client.ListFollowers(ctx, name, followsPageSize, offset)
+		}
+		return followsMsg{name: name, following: following, agents: agents, err: err, append: offset > 0}
+	}
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"net/url"
+	"os/exec"
+	"runtime"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// linkMsg reports the outcome of opening or copying a post's link.
+type linkMsg struct {
+	text string
+	err  error
+}
+
+// linkHost is the short form shown on feed cards.
+func linkHost(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return u.Host
+}
+
+func openLinkCmd(p *api.Post) tea.Cmd {
+	if p == nil || p.URL == "" {
+		return nil
+	}
+	link := p.URL
+	return func() tea.Msg {
+		if err := api.ValidateLinkURL(link); err != nil {
+			return linkMsg{err: err}
+		}
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", link)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+		default:
+			cmd = exec.Command("xdg-open", link)
+		}
+		if err := cmd.Start(); err != nil {
+			return linkMsg{err: err}
+		}
+		// Reap the launcher so it doesn't linger as a zombie
+		go cmd.Wait()
+		return linkMsg{text: "Opened " + linkHost(link)}
+	}
+}
+
+func copyLinkCmd(p *api.Post) tea.Cmd {
+	if p == nil || p.URL == "" {
+		return nil
+	}
+	link := p.URL
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(link); err != nil {
+			return linkMsg{err: err}
+		}
+		return linkMsg{text: "Copied link to clipboard"}
+	}
+}
+
+// highlightedPost is the post under the cursor in the feed or profile list.
+func (m Model) highlightedPost() *api.Post {
+	if m.selectedIndex < 0 || m.selectedIndex >= len(m.posts) {
+		return nil
+	}
+	return &m.posts[m.selectedIndex]
+}
@@ -1,17 +1,23 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/starkbaknet/moltbook-client/pkg/api"
 	"github.com/starkbaknet/moltbook-client/pkg/config"
+	"github.com/starkbaknet/moltbook-client/pkg/llm"
+	"github.com/starkbaknet/moltbook-client/pkg/safety"
 )
 
 type sessionState uint
@@ -25,6 +31,11 @@ const (
 	stateCreateComment
 	stateRegister
 	stateProfile
+	stateLogin
+	stateSubmolts
+	stateJumpSubmolt
+	stateSearch
+	stateFollows
 )
 
 type Model struct {
@@ -36,29 +47,76 @@ type Model struct {
 	// Feed components
 	posts         []api.Post
 	selectedIndex int
-	feedTitle     string
+	feed          feedSource
 	offset        int
 
+	// Submolt directory
+	submolts          []api.Submolt
+	submoltIndex      int
+	allSubmoltsLoaded bool
+	isLoadingSubmolts bool
+
+	// Search
+	searchQuery     string
+	searchType      string
+	searchResults   []api.SearchResult
+	searchIndex     int
+	searchOffset    int
+	searchFocused   bool // keys go to the query input rather than the results
+	allSearchLoaded bool
+	isSearching     bool
+
 	// Detail components
 	selectedPost *api.Post
 	comments     []api.Comment
+	thread       []threadEntry // comments in display order, see withComments
+	replyTo      *api.Comment  // comment being replied to in stateCreateComment
 	viewport     viewport.Model
 	feedViewport viewport.Model
 	ready        bool
 	commentIndex int
+	commentSort  string
+
+	// Comment paging
+	commentOffset     int
+	allCommentsLoaded bool
 
 	// Registration/Profile state
 	regStep      registerStep
 	regName      string
 	regDesc      string
 	regAgent     *api.Agent
+	profileName  string     // whose profile stateProfile shows
+	profile      *api.Agent // the agent on the profile screen
+
+	// Followers/following list
+	followsOf        string
+	showFollowing    bool // list whom followsOf follows rather than its followers
+	follows          []api.Agent
+	followsIndex     int
+	allFollowsLoaded bool
+	isLoadingFollows bool
 
 	// Create Post state
-	createStep   int // 0: Title, 1: Content
+	createStep   postStep
+	newPostSubmolt    string
+	pickerSubmolts    []api.Submolt
+	pickerIndex       int
+	isLoadingPicker   bool
+	isCheckingSubmolt bool
+	newPostLink  bool
 	newPostTitle string
 
 	// Inputs
 	textInput   textinput.Model
+	composer    textarea.Model // multi-line post body and comment editor
+
+	// Drafting assistant, see drafting.go
+	drafter    llm.ChatClient // nil unless the config has an llm section
+	draft      string
+	draftErr   string
+	isDrafting bool
+	showDraft  bool
 	spinner     spinner.Model
 	isLoading   bool // Full screen loading (initial load, refresh)
 	isPaginating bool // Background loading (infinite scroll)
@@ -71,7 +129,14 @@ type Model struct {
 	message     string
 	paginationErr error
 	allPostsLoaded bool
-	upvotedPosts   map[string]bool
+	postVotes      map[string]api.Vote
+	commentVotes   map[string]api.Vote
+	postSafety     map[string]safety.Result // by ID, see screenPosts
+	commentSafety  map[string]safety.Result
+	requests       *inflight
+
+	// Screens to return to, most recent last; see push and back
+	history []Model
 }
 
 func NewModel() Model {
@@ -96,15 +161,21 @@ func NewModel() Model {
 	dv.KeyMap = vpKeyMap
 
 	return Model{
-		state:        stateLoading,
-		textInput:    ti,
-		spinner:      s,
-		isLoading:    true,
-		help:         help.New(),
-		feedTitle:    "HOT FEED",
-		feedViewport: fv,
-		viewport:     dv,
-		upvotedPosts: make(map[string]bool),
+		state:         stateLoading,
+		textInput:     ti,
+		composer:      newComposer(),
+		spinner:       s,
+		isLoading:     true,
+		help:          help.New(),
+		feed:          hotFeed(),
+		commentSort:   api.CommentSortTop,
+		feedViewport:  fv,
+		viewport:      dv,
+		postVotes:     make(map[string]api.Vote),
+		commentVotes:  make(map[string]api.Vote),
+		postSafety:    make(map[string]safety.Result),
+		commentSafety: make(map[string]safety.Result),
+		requests:      newInflight(),
 	}
 }
 
@@ -117,12 +188,17 @@ func (m Model) Init() tea.Cmd {
 }
 
 type messageMsg string
-type upvoteSuccessMsg string
 type configLoadedMsg struct {
 	config *config.Config
 	client *api.Client
 }
 
+// newClient builds an API client, honoring MOLTBOOK_BASE_URL so the TUI can
+// be pointed at staging or a local mock server.
+func newClient(apiKey string) *api.Client {
+	return api.NewClient(apiKey, api.WithBaseURL(os.Getenv("MOLTBOOK_BASE_URL")))
+}
+
 func (m Model) loadConfigCmd() tea.Msg {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -130,7 +206,7 @@ func (m Model) loadConfigCmd() tea.Msg {
 	}
 	return configLoadedMsg{
 		config: cfg,
-		client: api.NewClient(cfg.APIKey),
+		client: newClient(cfg.APIKey),
 	}
 }
 
@@ -141,32 +217,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m = m.sizeComposer()
 
 	case tea.KeyMsg:
 		if m.err != nil {
 			switch msg.String() {
 			case "q":
 				return m, tea.Quit
-			case "r":
-				m.err = nil
-				m.isLoading = true
-				switch m.state {
-				case stateFeed:
-					if m.feedTitle == "HOT FEED" {
-						return m, m.fetchFeedCmd()
-					}
-					return m, m.fetchPersonalizedFeedCmd()
-				case statePostDetail:
-					if m.selectedPost != nil {
-						return m, m.fetchCommentsCmd(m.selectedPost.ID)
-					}
+			case "k":
+				if errors.Is(m.err, api.ErrUnauthorized) {
+					m.err = nil
+					m.state = stateLogin
+					m.textInput.Focus()
+					m.textInput.SetValue("")
+					m.textInput.Placeholder = "API Key"
 					return m, nil
-				case stateProfile:
-					return m, m.fetchMyProfileCmd()
-				default:
-					m.state = stateFeed
-					return m, m.fetchFeedCmd()
 				}
+			case "r":
+				var rl *api.RateLimitError
+				if errors.As(m.err, &rl) && time.Now().Before(rl.RetryAt) {
+					return m, nil // Still cooling down
+				}
+				return m.retry()
 			}
 			// Allow navigation keys to bypass error state
 		}
@@ -175,86 +247,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "q":
-			if m.state == stateFeed || m.state == statePostDetail || m.state == stateProfile {
+			if m.state == stateFeed || m.state == statePostDetail || m.state == stateProfile || m.state == stateSubmolts || m.state == stateFollows || m.state == stateSearch && !m.searchFocused {
 				return m, tea.Quit
 			}
 		case "esc":
-			m.err = nil
-			if m.state != stateFeed {
-				m.state = stateFeed
-				m.textInput.Blur()
-				m.message = ""
+			if m.isSubmitting && (m.state == stateCreatePost || m.state == stateCreateComment) {
+				// Leaving now could post twice if the user opens the
+				// composer again, so wait for the server's answer
+				m.message = "Still sending, wait for the server to answer..."
 				return m, nil
 			}
+			m.err = nil
+			if m.state == stateCreatePost {
+				break // Steps back within the composer, see updateCreatePost
+			}
+			if m.state != stateFeed || len(m.history) > 0 {
+				return m.back()
+			}
 		case "p":
 			m.err = nil
 			if m.state == stateFeed || m.state == stateProfile || m.err != nil {
-				m = m.resetFeed()
-				m.state = stateProfile
-				m.isLoading = true
-				m.message = ""
-				return m, m.fetchMyProfileCmd()
+				return m.openProfile("")
 			}
 		case "r":
 			m.err = nil
 			if m.state == stateFeed || m.err != nil {
 				m = m.resetFeed()
 				m.isLoading = true
-				if m.feedTitle == "HOT FEED" {
-					return m, m.fetchFeedCmd()
-				}
-				return m, m.fetchPersonalizedFeedCmd()
+				return m, m.fetchFeedCmd()
 			}
 		case "f":
 			m.err = nil
 			if m.state == stateFeed || m.err != nil {
-				m = m.resetFeed()
-				m.feedTitle = "PERSONALIZED FEED"
-				m.isLoading = true
-				return m, m.fetchPersonalizedFeedCmd()
+				return m.switchFeed(personalizedFeed())
 			}
 		case "h":
 			m.err = nil
 			if m.state == stateFeed || m.err != nil {
-				m = m.resetFeed()
-				m.feedTitle = "HOT FEED"
-				m.isLoading = true
-				m.message = ""
-				return m, m.fetchFeedCmd()
+				return m.switchFeed(hotFeed())
 			}
 
 		case "enter":
 			if (m.state == stateFeed || m.state == stateProfile) && len(m.posts) > 0 && m.selectedIndex >= 0 && m.selectedIndex < len(m.posts) {
-				m.selectedPost = &m.posts[m.selectedIndex]
-				m.state = statePostDetail
-				m.isLoadingComments = true
-				m.commentIndex = 0
-				m.comments = nil // Clear cache
-				m.message = ""
-				m.viewport.GotoTop()
-				
-				
-				// Force immediate content update to clear stale view buffer
-				if m.viewport.Width > 0 {
-					content, _ := m.renderDetailContent()
-					m.viewport.SetContent(content)
-				}
-				
-				m.ready = false // Force re-init of detail viewport if needed
-				return m, m.fetchCommentsCmd(m.selectedPost.ID)
+				return m.openPost(m.posts[m.selectedIndex])
 			}
 		case "u":
 			if (m.state == stateFeed || m.state == stateProfile) && len(m.posts) > 0 && m.selectedIndex >= 0 && m.selectedIndex < len(m.posts) {
-				return m, m.upvoteCmd(m.posts[m.selectedIndex].ID)
+				return m, m.votePostCmd(m.posts[m.selectedIndex].ID, api.VoteUp)
+			}
+		case "d":
+			if (m.state == stateFeed || m.state == stateProfile) && len(m.posts) > 0 && m.selectedIndex >= 0 && m.selectedIndex < len(m.posts) {
+				return m, m.votePostCmd(m.posts[m.selectedIndex].ID, api.VoteDown)
+			}
+		case "m":
+			if m.state == stateFeed {
+				return m.openSubmolts()
+			}
+		case "t":
+			// Jump to the submolt tag of the post under the cursor
+			if m.state == stateFeed || m.state == stateProfile {
+				if p := m.highlightedPost(); p != nil && p.Submolt.Name != "" {
+					return m.openFeed(submoltFeed(p.Submolt.Name))
+				}
+			}
+		case "/":
+			if m.state == stateFeed || m.state == stateProfile || m.state == stateSubmolts {
+				return m.startSearch(), nil
+			}
+		case "a":
+			// Author of the post under the cursor
+			if m.state == stateFeed {
+				if p := m.highlightedPost(); p != nil && p.Author.Name != "" {
+					return m.openProfile(p.Author.Name)
+				}
+			}
+		case "g":
+			if m.state == stateFeed || m.state == stateProfile {
+				return m.startJumpSubmolt(), nil
+			}
+		case "o", "y":
+			if m.state == stateFeed || m.state == stateProfile {
+				if msg.String() == "o" {
+					return m, openLinkCmd(m.highlightedPost())
+				}
+				return m, copyLinkCmd(m.highlightedPost())
 			}
 		case "n":
-			m.err = nil
-			m.state = stateCreatePost
-			m.textInput.Focus()
-			m.textInput.SetValue("")
-			m.createStep = 0
-			m.textInput.Placeholder = "Title"
-			return m, nil
+			if m.isTyping() {
+				break
+			}
+			return m.startCreatePost()
 		}
 
 	case spinner.TickMsg:
@@ -265,6 +347,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case configLoadedMsg:
 		m.config = msg.config
 		m.client = msg.client
+		m.drafter = newDrafter(msg.config.LLM)
 		m.state = stateFeed
 		m.isLoading = true
 		return m, m.fetchFeedCmd()
@@ -276,22 +359,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.isLoading = true
 			return m, m.fetchFeedCmd()
 		case stateCreatePost:
-			m.textInput.Focus()
-			m.textInput.SetValue("")
-			m.createStep = 0
-			m.textInput.Placeholder = "Title"
+			return m.startCreatePost()
 		case stateRegister:
 			m.textInput.Focus()
 			m.textInput.Placeholder = "Agent Name"
 			m.textInput.SetValue("")
 			m.regStep = stepName
 		case stateCreateComment:
-			m.textInput.Focus()
-			m.textInput.SetValue("")
-			m.textInput.Placeholder = "Share your thoughts..."
+			return m.startComposer("Share your thoughts...")
 		}
 
 	case feedMsg:
+		// Results for a feed we've since left
+		if isCanceled(msg.err) || m.state != stateFeed || msg.src != m.feed {
+			return m, nil
+		}
 		m.isLoading = false
 		m.isPaginating = false
 		if !msg.append {
@@ -305,29 +387,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Don't show the error, instead retry automatically after a short delay
 				m.isPaginating = true
 				m.paginationErr = nil
-				return m, func() tea.Msg {
-					time.Sleep(3 * time.Second)
-					return m.loadMoreCmd()()
-				}
+				return m, m.loadMoreAfterCmd(3 * time.Second)
 			}
 		} else {
 			m.paginationErr = nil
+			m.screenPosts(msg.posts)
 			if msg.append {
-				if len(msg.posts) > 0 {
-					m.posts = append(m.posts, msg.posts...)
-				}
-				if len(msg.posts) < 20 {
+				// The hot ranking shifts while scrolling, so a page can
+				// repeat posts we already have
+				fresh := dedupePosts(m.posts, msg.posts)
+				m.posts = append(m.posts, fresh...)
+				if len(msg.posts) < feedPageSize || len(fresh) == 0 {
 					m.allPostsLoaded = true
 				}
+				m.offset += len(msg.posts)
 			} else {
 				m.posts = msg.posts
 				m.selectedIndex = 0
 				m.feedViewport.GotoTop()
-				if len(m.posts) < 20 {
+				if len(m.posts) < feedPageSize {
 					m.allPostsLoaded = true
 				}
+				m.offset = len(m.posts)
 			}
-			m.offset = len(m.posts)
 			m.err = nil
 		}
 		
@@ -338,10 +420,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case profileMsg:
+		if isCanceled(msg.err) || m.state != stateProfile || msg.name != m.profileName {
+			return m, nil
+		}
 		m.isLoading = false
 		m.isSubmitting = false
-		m.regAgent = msg.agent
+		m.profile = msg.agent
 		m.posts = msg.posts
+		m.screenPosts(msg.posts)
 		m.err = msg.err
 		m.selectedIndex = 0
 		m.feedViewport.GotoTop()
@@ -350,7 +436,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case commentsMsg:
 		// Ignore stale messages from previous posts
-		if m.selectedPost == nil || msg.postID != "" && msg.postID != m.selectedPost.ID {
+		if m.state != statePostDetail || m.selectedPost == nil || msg.postID != "" && msg.postID != m.selectedPost.ID {
+			return m, nil
+		}
+		if isCanceled(msg.err) {
 			return m, nil
 		}
 
@@ -366,11 +455,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.message = "Failed to load comments: " + msg.err.Error()
 			}
 		} else {
+			m.screenComments(msg.comments)
 			if msg.append {
-				m.comments = append(m.comments, msg.comments...)
+				m = m.withComments(append(m.comments, msg.comments...))
+				m.commentOffset += len(msg.comments)
 			} else {
-				m.comments = msg.comments
+				m = m.withComments(msg.comments)
+				m.commentOffset = len(msg.comments)
 			}
+			m.allCommentsLoaded = len(msg.comments) < commentPageSize
 			m.err = nil
 		}
 		
@@ -381,26 +474,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case postCreatedMsg:
-		m.isLoading = false
+		// Only the composer that sent it may act on the reply
+		if isCanceled(msg.err) || m.state != stateCreatePost || !m.isSubmitting {
+			return m, nil
+		}
 		m.isSubmitting = false
 		if msg.err != nil {
-			m.err = msg.err
+			// Stay in the composer so the post isn't lost; ctrl+s retries
+			m.isLoading = false
+			m.message = "Posting failed: " + msg.err.Error()
+			return m, nil
 		}
-		m.state = stateFeed
-		m.isLoading = true
-		return m, m.fetchFeedCmd()
+		m = m.pop()
+		m.message = "Posted! 🦞"
+		if m.state == stateFeed {
+			m = m.resetFeed()
+			m.isLoading = true
+			return m, m.fetchFeedCmd()
+		}
+		return m, m.resumeCmd()
 
 	case commentCreatedMsg:
+		if isCanceled(msg.err) || m.state != stateCreateComment || !m.isSubmitting {
+			return m, nil
+		}
 		m.isSubmitting = false
 		if msg.err != nil {
-			m.err = msg.err
+			m.message = "Comment failed: " + msg.err.Error()
 			return m, nil
 		}
-		m.state = statePostDetail
-		m.textInput.Blur()
-		m.textInput.SetValue("")
+		m = m.pop()
+		if m.state != statePostDetail || m.selectedPost == nil {
+			return m, m.resumeCmd()
+		}
+		m.composer.Blur()
 		m.isLoadingComments = true
-		m.comments = nil // Clear cache to reload
+		m.commentIndex = 0
+		m.replyTo = nil
+		m = m.withComments(nil) // Clear cache to reload
 		// We re-fetch comments
 		return m, m.fetchCommentsCmd(m.selectedPost.ID)
 
@@ -408,28 +519,99 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.message = string(msg)
 		return m, nil
 
-	case upvoteSuccessMsg:
-		id := string(msg)
-		m.upvotedPosts[id] = true
-		for i := range m.posts {
-			if m.posts[i].ID == id {
-				m.posts[i].Upvotes++
+	case searchMsg:
+		if isCanceled(msg.err) || m.state != stateSearch {
+			return m, nil
+		}
+		return m.applySearch(msg), nil
+
+	case followsMsg:
+		if isCanceled(msg.err) || m.state != stateFollows {
+			return m, nil
+		}
+		return m.applyFollows(msg), nil
+
+	case searchPostMsg:
+		if isCanceled(msg.err) || m.state != stateSearch {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = "Couldn't open post: " + msg.err.Error()
+			return m, nil
+		}
+		return m.openPost(*msg.post)
+
+	case submoltsMsg:
+		if isCanceled(msg.err) || m.state != stateSubmolts {
+			return m, nil
+		}
+		m.isLoadingSubmolts = false
+		if msg.err != nil {
+			if msg.append {
+				m.message = "Failed to load submolts: " + msg.err.Error()
+			} else {
+				m.err = msg.err
 			}
+			return m, nil
 		}
-		if m.selectedPost != nil && m.selectedPost.ID == id {
-			m.selectedPost.Upvotes++
+		if msg.append {
+			m.submolts = append(m.submolts, msg.submolts...)
+		} else {
+			m.submolts = msg.submolts
 		}
+		m.allSubmoltsLoaded = len(msg.submolts) < submoltPageSize
+		return m, nil
 
-		// ESSENTIAL: Update viewports to show the [UPVOTED] tag and new count
-		if m.feedViewport.Width > 0 {
-			content, _ := m.renderFeedContent()
-			m.feedViewport.SetContent(content)
+	case subscribeMsg:
+		if msg.err != nil {
+			m.message = "Subscription failed: " + msg.err.Error()
+			return m, nil
 		}
-		if m.viewport.Width > 0 {
-			content, _ := m.renderDetailContent()
-			m.viewport.SetContent(content)
+		m = m.updateScreens(func(s Model) Model { return s.applySubscription(msg) })
+		if msg.subscribed {
+			m.message = "Subscribed to m/" + msg.name
+		} else {
+			m.message = "Unsubscribed from m/" + msg.name
+		}
+		return m, nil
+
+	case followMsg:
+		m.isSubmitting = false
+		if msg.err != nil {
+			m.message = "Follow failed: " + msg.err.Error()
+			return m, nil
+		}
+		m = m.updateScreens(func(s Model) Model { return s.applyFollow(msg) })
+		if msg.following {
+			m.message = "Following " + msg.name + "! 🦞"
+		} else {
+			m.message = "Unfollowed " + msg.name
+		}
+		return m, nil
+
+	case linkMsg:
+		if msg.err != nil {
+			m.message = "Link failed: " + msg.err.Error()
+		} else {
+			m.message = msg.text
 		}
+		return m, nil
 
+	case voteMsg:
+		if msg.err != nil {
+			m.message = "Vote failed: " + msg.err.Error()
+			return m, nil
+		}
+		var old api.Vote
+		if msg.postID != "" {
+			old = m.postVotes[msg.postID]
+			m.postVotes[msg.postID] = msg.vote
+		} else {
+			old = m.commentVotes[msg.commentID]
+			m.commentVotes[msg.commentID] = msg.vote
+		}
+		// Saved screens may show the same post, so update them too
+		m = m.updateScreens(func(s Model) Model { return s.applyVote(msg, old) })
 		return m, nil
 
 	case errMsg:
@@ -454,12 +636,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m, cmd = m.updateRegister(msg)
 	case stateProfile:
 		m, cmd = m.updateProfile(msg)
+	case stateLogin:
+		m, cmd = m.updateLogin(msg)
+	case stateSubmolts:
+		m, cmd = m.updateSubmolts(msg)
+	case stateJumpSubmolt:
+		m, cmd = m.updateJumpSubmolt(msg)
+	case stateSearch:
+		m, cmd = m.updateSearch(msg)
+	case stateFollows:
+		m, cmd = m.updateFollows(msg)
 	}
 
 	return m, cmd
 }
 
+// isTyping reports whether the current state routes keys into a text input,
+// in which case single-letter shortcuts must not fire.
+func (m Model) isTyping() bool {
+	switch m.state {
+	case stateCreatePost, stateCreateComment, stateRegister, stateLogin, stateJumpSubmolt:
+		return true
+	case stateSearch:
+		return m.searchFocused
+	}
+	return false
+}
+
 type feedMsg struct {
+	src    feedSource
 	posts  []api.Post
 	err    error
 	append bool
@@ -467,56 +672,66 @@ type feedMsg struct {
 
 
 
+// dedupePosts returns the posts in next that aren't already in have.
+func dedupePosts(have, next []api.Post) []api.Post {
+	seen := make(map[string]bool, len(have))
+	for _, p := range have {
+		seen[p.ID] = true
+	}
+	var fresh []api.Post
+	for _, p := range next {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			fresh = append(fresh, p)
+		}
+	}
+	return fresh
+}
+
 func (m Model) resetFeed() Model {
 	m.offset = 0
 	m.posts = []api.Post{}
 	m.selectedIndex = 0
+	m.isPaginating = false
 	m.feedViewport.GotoTop()
 	return m
 }
 
 func (m Model) fetchFeedCmd() tea.Cmd {
+	ctx := m.requests.start(reqFeed)
+	src, offset := m.feed, m.offset
 	return func() tea.Msg {
 		if m.client == nil {
-			return feedMsg{err: fmt.Errorf("client not initialized")}
+			return feedMsg{src: src, err: fmt.Errorf("client not initialized")}
 		}
-		posts, err := m.client.GetFeed("hot", 20, m.offset)
-		return feedMsg{posts: posts, err: err, append: m.offset > 0}
+		posts, err := src.fetch(ctx, m.client, offset)
+		return feedMsg{src: src, posts: posts, err: err, append: offset > 0}
 	}
 }
 
 func (m Model) loadMoreCmd() tea.Cmd {
-	return func() tea.Msg {
-		if m.client == nil {
-			return feedMsg{err: fmt.Errorf("client not initialized")}
-		}
-		
-		var posts []api.Post
-		var err error
-		
-		// Use current post count as offset
-		currentOffset := len(m.posts)
-		
-		if m.feedTitle == "HOT FEED" {
-			posts, err = m.client.GetFeed("hot", 20, currentOffset)
-		} else if m.feedTitle == "SEARCH RESULTS" {
-			// Search doesn't currently support offset in this client easily, return empty to reset spinner
-			return func() tea.Msg { return feedMsg{append: true} }
-		} else {
-			posts, err = m.client.GetPersonalizedFeed("hot", 20, currentOffset)
-		}
-		
-		return feedMsg{posts: posts, err: err, append: true}
-	}
+	return m.loadMoreAfterCmd(0)
 }
 
-func (m Model) fetchPersonalizedFeedCmd() tea.Cmd {
+// loadMoreAfterCmd loads the next page after delay. Leaving the feed
+// cancels the wait along with the request.
+func (m Model) loadMoreAfterCmd(delay time.Duration) tea.Cmd {
+	ctx := m.requests.start(reqFeed)
+	// Server-side offset, which can run ahead of len(m.posts) after dedupe
+	src, offset := m.feed, m.offset
 	return func() tea.Msg {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return feedMsg{src: src, err: ctx.Err(), append: true}
+			}
+		}
 		if m.client == nil {
-			return feedMsg{err: fmt.Errorf("client not initialized")}
+			return feedMsg{src: src, err: fmt.Errorf("client not initialized")}
 		}
-		posts, err := m.client.GetPersonalizedFeed("hot", 20, m.offset)
-		return feedMsg{posts: posts, err: err, append: m.offset > 0}
+		posts, err := src.fetch(ctx, m.client, offset)
+		return feedMsg{src: src, posts: posts, err: err, append: true}
 	}
 }
 
@@ -524,15 +739,11 @@ type errMsg struct{ err error }
 
 func (m Model) View() string {
 	if m.err != nil {
-		return lipgloss.JoinVertical(lipgloss.Left,
-			TitleStyle.Background(lipgloss.Color("#ff0000")).Render(" ERROR "),
-			"\n"+lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true).Render(m.err.Error()),
-			"\n"+HelpStyle.Render("Press 'r' to retry • 'q' to quit"),
-		)
+		return m.errorView()
 	}
 
 	// Full-screen loading only if we have no content and aren't in a creation state
-	if m.isLoading && len(m.posts) == 0 && m.state != stateCreatePost && m.state != stateRegister {
+	if m.isLoading && len(m.posts) == 0 && m.state != stateCreatePost && m.state != stateRegister && m.state != stateLogin {
 		return fmt.Sprintf("\n\n   %s Loading Moltbook...\n   Please wait, AI swarms are busy...\n\n", m.spinner.View())
 	}
 
@@ -551,7 +762,49 @@ func (m Model) View() string {
 		return m.registerView()
 	case stateProfile:
 		return m.profileView()
+	case stateLogin:
+		return m.loginView()
+	case stateSubmolts:
+		return m.submoltsView()
+	case stateJumpSubmolt:
+		return m.jumpSubmoltView()
+	case stateSearch:
+		return m.searchView()
+	case stateFollows:
+		return m.followsView()
 	default:
 		return "Unknown state"
 	}
 }
+
+// errorView picks a recovery action based on what kind of failure m.err is.
+// The rate limit countdown is re-rendered on every spinner tick.
+func (m Model) errorView() string {
+	title := " ERROR "
+	help := "Press 'r' to retry • 'q' to quit"
+
+	var rl *api.RateLimitError
+	var netErr *api.NetworkError
+	switch {
+	case errors.As(m.err, &rl):
+		title = " RATE LIMITED "
+		if remaining := time.Until(rl.RetryAt); remaining > 0 {
+			help = fmt.Sprintf("Retry available in %ds • 'q' to quit", int(math.Ceil(remaining.Seconds())))
+		}
+	case errors.Is(m.err, api.ErrUnauthorized):
+		title = " UNAUTHORIZED "
+		help = "Your API key was rejected. Press 'k' to enter a new key • 'q' to quit"
+	case errors.Is(m.err, api.ErrNotFound):
+		title = " NOT FOUND "
+		help = "It may have been deleted. Press 'esc' to go back • 'q' to quit"
+	case errors.As(m.err, &netErr):
+		title = " NETWORK ERROR "
+		help = "Check your connection. Press 'r' to retry • 'q' to quit"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		TitleStyle.Background(lipgloss.Color("#ff0000")).Render(title),
+		"\n"+lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true).Render(m.err.Error()),
+		"\n"+HelpStyle.Render(help),
+	)
+}
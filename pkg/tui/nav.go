@@ -0,0 +1,187 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// maxHistory bounds how many screens back can return through.
+const maxHistory = 50
+
+// push saves the current screen so back can return to it exactly as it was,
+// then stops its in-flight loads; back restarts them. Call it before
+// switching to another screen.
+func (m Model) push() Model {
+	m.history = append(m.history, m.snapshot())
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+	m.requests.cancelAll()
+	m.err = nil
+	m.message = ""
+	m.isSubmitting = false
+	return m
+}
+
+// snapshot copies the screen state that later updates mutate in place, so
+// the saved screen and the live one can't see each other's changes.
+func (m Model) snapshot() Model {
+	m.history = nil
+	// push cancels a follow or delete in flight and nothing resumes it
+	m.isSubmitting = false
+	m.posts = append([]api.Post(nil), m.posts...)
+	m.comments = cloneComments(m.comments)
+	m = m.withComments(m.comments)
+	m.submolts = append([]api.Submolt(nil), m.submolts...)
+	m.follows = append([]api.Agent(nil), m.follows...)
+	m.searchResults = append([]api.SearchResult(nil), m.searchResults...)
+	if m.selectedPost != nil {
+		post := *m.selectedPost
+		m.selectedPost = &post
+	}
+	if m.profile != nil {
+		agent := *m.profile
+		m.profile = &agent
+	}
+	return m
+}
+
+func cloneComments(comments []api.Comment) []api.Comment {
+	if comments == nil {
+		return nil
+	}
+	out := make([]api.Comment, len(comments))
+	for i, c := range comments {
+		c.Replies = cloneComments(c.Replies)
+		out[i] = c
+	}
+	return out
+}
+
+// pop returns the previous screen without restarting its loads. With no
+// history it lands on the feed.
+func (m Model) pop() Model {
+	m.requests.cancelAll()
+	if len(m.history) == 0 {
+		m.state = stateFeed
+		m.err = nil
+		m.message = ""
+		m.textInput.Blur()
+		return m
+	}
+	prev := m.history[len(m.history)-1]
+	prev.history = m.history[:len(m.history)-1]
+
+	// Session-wide state isn't part of a screen
+	prev.client = m.client
+	prev.config = m.config
+	prev.spinner = m.spinner
+	if prev.width != m.width || prev.height != m.height {
+		prev.width, prev.height = m.width, m.height
+		prev.feedViewport.Width = 0 // Re-measured by updateFeed
+		prev.ready = false
+	}
+	return prev.refreshViewports()
+}
+
+// back pops to the previous screen and resumes whatever it was loading.
+func (m Model) back() (Model, tea.Cmd) {
+	wasFeed := m.state == stateFeed && len(m.history) == 0
+	m = m.pop()
+	if wasFeed {
+		return m, nil
+	}
+	if m.state == stateFeed && len(m.posts) == 0 && !m.isLoading {
+		m.isLoading = true
+	}
+	return m, m.resumeCmd()
+}
+
+// resumeCmd restarts the load that was running when the screen was pushed.
+func (m Model) resumeCmd() tea.Cmd {
+	switch m.state {
+	case stateFeed:
+		if m.isLoading {
+			return m.fetchFeedCmd()
+		}
+		if m.isPaginating {
+			return m.loadMoreCmd()
+		}
+	case statePostDetail:
+		if m.isLoadingComments && m.selectedPost != nil {
+			if len(m.comments) == 0 {
+				return m.fetchCommentsCmd(m.selectedPost.ID)
+			}
+			return m.loadMoreCommentsCmd()
+		}
+	case stateProfile:
+		if m.isLoading {
+			return m.fetchProfileCmd(m.profileName)
+		}
+	case stateSubmolts:
+		if m.isLoadingSubmolts {
+			return m.fetchSubmoltsCmd(len(m.submolts))
+		}
+	case stateSearch:
+		if m.isSearching {
+			return m.searchCmd(m.searchOffset)
+		}
+	case stateFollows:
+		if m.isLoadingFollows {
+			return m.fetchFollowsCmd(len(m.follows))
+		}
+	}
+	return nil
+}
+
+// retry reloads the current screen after an error.
+func (m Model) retry() (Model, tea.Cmd) {
+	m.err = nil
+	switch m.state {
+	case stateFeed:
+		m.isLoading = true
+		return m, m.fetchFeedCmd()
+	case statePostDetail:
+		if m.selectedPost == nil {
+			return m.back()
+		}
+		m.isLoadingComments = true
+		return m, m.fetchCommentsCmd(m.selectedPost.ID)
+	case stateProfile:
+		m.isLoading = true
+		return m, m.fetchProfileCmd(m.profileName)
+	case stateSubmolts:
+		m.isLoadingSubmolts = true
+		return m, m.fetchSubmoltsCmd(len(m.submolts))
+	case stateSearch:
+		m.isSearching = true
+		return m, m.searchCmd(m.searchOffset)
+	case stateFollows:
+		m.isLoadingFollows = true
+		return m, m.fetchFollowsCmd(len(m.follows))
+	default:
+		return m.back()
+	}
+}
+
+// updateScreens applies f to the live screen and every saved one, for
+// changes like votes that should show wherever the item appears.
+func (m Model) updateScreens(f func(Model) Model) Model {
+	for i := range m.history {
+		m.history[i] = f(m.history[i])
+	}
+	return f(m)
+}
+
+// refreshViewports re-renders the viewport content from the model.
+func (m Model) refreshViewports() Model {
+	if m.feedViewport.Width > 0 {
+		content, _ := m.renderFeedContent()
+		m.feedViewport.SetContent(content)
+	}
+	if m.viewport.Width > 0 && m.selectedPost != nil {
+		content, _ := m.renderDetailContent()
+		m.viewport.SetContent(content)
+	}
+	return m
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,15 +10,55 @@ import (
 	"github.com/starkbaknet/moltbook-client/pkg/api"
 )
 
+// openProfile shows name's profile; an empty name means the logged-in agent.
+func (m Model) openProfile(name string) (Model, tea.Cmd) {
+	if name == "" && m.config != nil {
+		name = m.config.AgentName
+	}
+	// Already there: just reload
+	if m.state != stateProfile || m.profileName != name {
+		m = m.push()
+	}
+	m = m.resetFeed()
+	m.profileName = name
+	m.profile = nil
+	m.state = stateProfile
+	m.isLoading = true
+	m.message = ""
+	m.textInput.Blur()
+	return m, m.fetchProfileCmd(name)
+}
+
+// isMyProfile reports whether the profile on screen is the logged-in agent's.
+func (m Model) isMyProfile() bool {
+	return m.config != nil && m.profileName == m.config.AgentName
+}
+
 func (m Model) updateProfile(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "x": // Shortcut to delete post
+			if !m.isMyProfile() {
+				break
+			}
 			if !m.isSubmitting && len(m.posts) > 0 && m.selectedIndex >= 0 && m.selectedIndex < len(m.posts) {
 				m.isSubmitting = true
 				return m, m.deletePostCmd(m.posts[m.selectedIndex].ID)
 			}
+		case "f":
+			if m.profile != nil && !m.isMyProfile() && !m.isSubmitting {
+				m.isSubmitting = true
+				return m, m.followCmd(m.profile.Name, !m.profile.IsFollowing)
+			}
+		case "l":
+			if m.profile != nil {
+				return m.openFollows(m.profile.Name, false)
+			}
+		case "L":
+			if m.profile != nil {
+				return m.openFollows(m.profile.Name, true)
+			}
 		case "j", "down":
 			if m.selectedIndex < len(m.posts)-1 {
 				m.selectedIndex++
@@ -32,30 +73,42 @@ func (m Model) updateProfile(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m Model) profileView() string {
-	if m.regAgent == nil {
+	if m.profile == nil {
 		return "Loading profile..."
 	}
 
 	var s strings.Builder
-	title := fmt.Sprintf(" PROFILE: %s ", m.regAgent.Name)
-	s.WriteString(TitleStyle.Render(title) + "\n\n")
+	title := fmt.Sprintf(" PROFILE: %s ", m.profile.Name)
+	header := TitleStyle.Render(title)
+	if !m.isMyProfile() {
+		header += "  " + followBadge(m.profile.IsFollowing)
+	}
+	s.WriteString(header + "\n\n")
 	
-	s.WriteString(lipgloss.NewStyle().Bold(true).Render("Description: ") + m.regAgent.Description + "\n")
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render("Description: ") + m.profile.Description + "\n")
 	s.WriteString(fmt.Sprintf("%s · %s · %s\n", 
-		lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("%d Karma", m.regAgent.Karma)),
-		fmt.Sprintf("%d Followers", m.regAgent.FollowerCount),
-		fmt.Sprintf("%d Following", m.regAgent.FollowingCount),
+		lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("%d Karma", m.profile.Karma)),
+		fmt.Sprintf("%d Followers", m.profile.FollowerCount),
+		fmt.Sprintf("%d Following", m.profile.FollowingCount),
 	))
 	
 	status := "Claimed ✅"
-	if !m.regAgent.IsClaimed {
+	if !m.profile.IsClaimed {
 		status = "Pending Claim ⏳"
 	}
 	s.WriteString(fmt.Sprintf("Status: %s\n\n", status))
 
-	s.WriteString(HeaderStyle.Render("MY RECENT POSTS") + "\n")
+	if m.isMyProfile() {
+		s.WriteString(HeaderStyle.Render("MY RECENT POSTS") + "\n")
+	} else {
+		s.WriteString(HeaderStyle.Render("RECENT POSTS") + "\n")
+	}
 	if len(m.posts) == 0 {
-		s.WriteString("You haven't posted anything yet.\n")
+		if m.isMyProfile() {
+			s.WriteString("You haven't posted anything yet.\n")
+		} else {
+			s.WriteString(m.profile.Name + " hasn't posted anything yet.\n")
+		}
 	}
 
 	for i, post := range m.posts {
@@ -67,7 +120,7 @@ func (m Model) profileView() string {
 		title := post.Title
 		if title == "" { title = "Post" }
 		
-		meta := fmt.Sprintf("%d 🦞 · %s", post.Upvotes, post.CreatedAt.Format("2006-01-02"))
+		meta := fmt.Sprintf("%s · %s%s", voteCount(post.Upvotes, post.Downvotes), post.CreatedAt.Format("2006-01-02"), voteBadge(m.postVotes[post.ID]))
 		
 		card := style.Width(m.width - 4).Render(
 			fmt.Sprintf("%s\n%s", lipgloss.NewStyle().Bold(true).Render(title), meta),
@@ -75,32 +128,96 @@ func (m Model) profileView() string {
 		s.WriteString(card + "\n")
 	}
 
-	s.WriteString("\n" + HelpStyle.Render("esc: back • enter: view • x: delete post • q: quit"))
+	if m.message != "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render("• "+m.message) + "\n")
+	}
+
+	help := "esc: back • enter: view • u/d: vote • o/y: link • l/L: followers/following • x: delete post • q: quit"
+	if !m.isMyProfile() {
+		help = "esc: back • enter: view • u/d: vote • o/y: link • l/L: followers/following • f: follow/unfollow • q: quit"
+	}
+	s.WriteString("\n" + HelpStyle.Render(help))
 	return s.String()
 }
 
+func followBadge(following bool) string {
+	if following {
+		return lipgloss.NewStyle().Foreground(AccentColor).Render("✓ Following")
+	}
+	return lipgloss.NewStyle().Foreground(GrayColor).Render("Not following")
+}
+
+// applyFollow reflects a finished follow or unfollow wherever the agent
+// appears on this screen.
+func (m Model) applyFollow(msg followMsg) Model {
+	delta := -1
+	if msg.following {
+		delta = 1
+	}
+	for i := range m.follows {
+		if m.follows[i].Name == msg.name && m.follows[i].IsFollowing != msg.following {
+			m.follows[i].IsFollowing = msg.following
+			m.follows[i].FollowerCount += delta
+		}
+	}
+	if m.profile != nil && m.profile.Name == msg.name && m.profile.IsFollowing != msg.following {
+		m.profile.IsFollowing = msg.following
+		m.profile.FollowerCount += delta
+	}
+	return m
+}
+
 type profileMsg struct {
+	name  string
 	agent *api.Agent
 	posts []api.Post
 	err   error
 }
 
+// followMsg reports a finished follow or unfollow of name.
+type followMsg struct {
+	name      string
+	following bool
+	err       error
+}
+
+func (m Model) fetchProfileCmd(name string) tea.Cmd {
+	ctx := m.requests.start(reqProfile)
+	return func() tea.Msg {
+		if m.client == nil || name == "" {
+			return profileMsg{name: name, err: fmt.Errorf("not logged in")}
+		}
+		agent, posts, err := m.client.GetProfile(ctx, name)
+		return profileMsg{name: name, agent: agent, posts: posts, err: err}
+	}
+}
+
 func (m Model) fetchMyProfileCmd() tea.Cmd {
+	if m.config == nil {
+		return m.fetchProfileCmd("")
+	}
+	return m.fetchProfileCmd(m.config.AgentName)
+}
+
+func (m Model) followCmd(name string, follow bool) tea.Cmd {
 	return func() tea.Msg {
-		if m.client == nil || m.config == nil {
-			return profileMsg{err: fmt.Errorf("not logged in")}
+		var err error
+		if follow {
+			err = m.client.Follow(context.Background(), name)
+		} else {
+			err = m.client.Unfollow(context.Background(), name)
 		}
-		agent, posts, err := m.client.GetProfile(m.config.AgentName)
-		return profileMsg{agent: agent, posts: posts, err: err}
+		return followMsg{name: name, following: follow, err: err}
 	}
 }
 
 func (m Model) deletePostCmd(id string) tea.Cmd {
+	reload := m.fetchMyProfileCmd()
 	return func() tea.Msg {
-		err := m.client.DeletePost(id)
+		err := m.client.DeletePost(context.Background(), id)
 		if err != nil {
 			return errMsg{err}
 		}
-		return m.fetchMyProfileCmd()()
+		return reload()
 	}
 }
@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -38,10 +40,12 @@ func (m Model) updateRegister(msg tea.Msg) (Model, tea.Cmd) {
 				m.isSubmitting = true
 				return m, m.registerCmd
 			} else if m.regStep == stepSuccess {
+				m.history = nil
 				m.state = stateFeed
 				return m, m.fetchFeedCmd()
 			}
 		case "esc":
+			m.history = nil
 			m.state = stateFeed
 			return m, nil
 		}
@@ -54,11 +58,12 @@ func (m Model) updateRegister(msg tea.Msg) (Model, tea.Cmd) {
 		m.regAgent = msg.agent
 		m.regStep = stepSuccess
 		// Initialize client now that we have a key
-		m.client = api.NewClient(msg.agent.APIKey)
+		m.client = newClient(msg.agent.APIKey)
 		// Save to config
 		m.config = &config.Config{
 			APIKey:    msg.agent.APIKey,
 			AgentName: msg.agent.Name,
+			LLM:       m.llmConfig(), // Keep the assistant across re-logins
 		}
 		config.SaveConfig(m.config)
 		return m, nil
@@ -107,6 +112,74 @@ type registerResponseMsg struct {
 }
 
 func (m Model) registerCmd() tea.Msg {
-	agent, err := api.NewClient("").Register(m.regName, m.regDesc)
+	agent, err := newClient("").Register(context.Background(), m.regName, m.regDesc)
 	return registerResponseMsg{agent: agent, err: err}
 }
+
+func (m Model) updateLogin(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			key := strings.TrimSpace(m.textInput.Value())
+			if key == "" || m.isSubmitting {
+				return m, nil
+			}
+			m.isSubmitting = true
+			return m, m.loginCmd(key)
+		}
+	case loginResponseMsg:
+		m.isSubmitting = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.client = msg.client
+		m.config = &config.Config{
+			APIKey:    msg.client.APIKey,
+			AgentName: msg.agent.Name,
+			LLM:       m.llmConfig(), // Keep the assistant across re-logins
+		}
+		config.SaveConfig(m.config)
+		m.textInput.Blur()
+		// A new key starts a fresh session, so drop the old screens
+		m.history = nil
+		m = m.resetFeed()
+		m.state = stateFeed
+		m.isLoading = true
+		return m, m.fetchFeedCmd()
+	}
+
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) loginView() string {
+	status := HelpStyle.Render("enter: verify & save • esc: cancel")
+	if m.isSubmitting {
+		status = fmt.Sprintf("%s Checking key...", m.spinner.View())
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		TitleStyle.Render(" ENTER API KEY "),
+		"\nPaste the API key for your agent:",
+		m.textInput.View(),
+		"\n"+status,
+	))
+}
+
+type loginResponseMsg struct {
+	client *api.Client
+	agent  *api.Agent
+	err    error
+}
+
+// loginCmd verifies the key against /agents/me before we persist it.
+func (m Model) loginCmd(key string) tea.Cmd {
+	return func() tea.Msg {
+		client := newClient(key)
+		agent, err := client.GetMe(context.Background())
+		return loginResponseMsg{client: client, agent: agent, err: err}
+	}
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"context"
+	"errors"
+)
+
+type requestKind uint
+
+const (
+	reqFeed requestKind = iota
+	reqComments
+	reqProfile
+	reqSubmolts
+	reqSearch
+	reqFollows
+	reqPicker
+	reqSubmoltCheck
+	reqDraft
+	reqSubmit
+)
+
+// inflight remembers the cancel func of the latest fetch per view so a newer
+// fetch, or leaving the view, aborts the one still running.
+// It is held by pointer so the value-receiver commands can share it.
+type inflight struct {
+	cancels map[requestKind]context.CancelFunc
+}
+
+func newInflight() *inflight {
+	return &inflight{cancels: make(map[requestKind]context.CancelFunc)}
+}
+
+// start cancels any previous request of the same kind and returns the
+// context for the new one. Must be called from Update, not from inside a Cmd.
+func (f *inflight) start(kind requestKind) context.Context {
+	f.cancel(kind)
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancels[kind] = cancel
+	return ctx
+}
+
+func (f *inflight) cancel(kind requestKind) {
+	if cancel, ok := f.cancels[kind]; ok {
+		cancel()
+		delete(f.cancels, kind)
+	}
+}
+
+// cancelAll aborts every running fetch, e.g. when the screen changes.
+func (f *inflight) cancelAll() {
+	for kind := range f.cancels {
+		f.cancel(kind)
+	}
+}
+
+// isCanceled reports whether err came from a request we aborted ourselves,
+// in which case the result should be dropped silently.
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+const searchPageSize = 20
+
+var searchTypes = []string{api.SearchTypePosts, api.SearchTypeComments, api.SearchTypeAgents, api.SearchTypeAll}
+
+type searchMsg struct {
+	query   string
+	results []api.SearchResult
+	err     error
+	append  bool
+}
+
+// searchPostMsg carries the post a comment hit belongs to.
+type searchPostMsg struct {
+	post *api.Post
+	err  error
+}
+
+// startSearch opens the search prompt, keeping the last query and results.
+func (m Model) startSearch() Model {
+	if m.state != stateSearch {
+		m = m.push()
+	}
+	m.state = stateSearch
+	m.isLoading = false
+	m.message = ""
+	if m.searchType == "" {
+		m.searchType = api.SearchTypePosts
+	}
+	m.searchFocused = true
+	m.textInput.Focus()
+	m.textInput.SetValue(m.searchQuery)
+	m.textInput.CursorEnd()
+	m.textInput.Placeholder = "Search Moltbook..."
+	return m
+}
+
+// runSearch starts a fresh search for the prompt's current value.
+func (m Model) runSearch() (Model, tea.Cmd) {
+	query := strings.TrimSpace(m.textInput.Value())
+	if query == "" {
+		return m, nil
+	}
+	m.searchQuery = query
+	m.searchResults = nil
+	m.searchIndex = 0
+	m.searchOffset = 0
+	m.allSearchLoaded = false
+	m.isSearching = true
+	m.searchFocused = false
+	m.textInput.Blur()
+	m.message = ""
+	return m, m.searchCmd(0)
+}
+
+func nextSearchType(current string) string {
+	for i, t := range searchTypes {
+		if t == current {
+			return searchTypes[(i+1)%len(searchTypes)]
+		}
+	}
+	return searchTypes[0]
+}
+
+func (m Model) updateSearch(msg tea.Msg) (Model, tea.Cmd) {
+	key, isKey := msg.(tea.KeyMsg)
+	if m.searchFocused {
+		if isKey {
+			switch key.String() {
+			case "enter":
+				return m.runSearch()
+			case "tab":
+				m.searchType = nextSearchType(m.searchType)
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+	if !isKey {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "j", "down":
+		if m.searchIndex < len(m.searchResults)-1 {
+			m.searchIndex++
+			if m.searchIndex >= len(m.searchResults)-2 && !m.isSearching && !m.allSearchLoaded {
+				m.isSearching = true
+				return m, m.searchCmd(m.searchOffset)
+			}
+		}
+	case "k", "up":
+		if m.searchIndex > 0 {
+			m.searchIndex--
+		}
+	case "/":
+		m.searchFocused = true
+		m.textInput.Focus()
+		m.textInput.CursorEnd()
+	case "tab":
+		m.searchType = nextSearchType(m.searchType)
+		m.textInput.SetValue(m.searchQuery)
+		return m.runSearch()
+	case "enter":
+		if m.searchIndex < 0 || m.searchIndex >= len(m.searchResults) {
+			return m, nil
+		}
+		hit := m.searchResults[m.searchIndex]
+		switch hit.Kind {
+		case api.SearchKindPost:
+			return m.openPost(*hit.Post)
+		case api.SearchKindComment:
+			m.message = "Opening post..."
+			m.isSearching = false // The fetch below supersedes any page still loading
+			return m, m.fetchSearchPostCmd(hit.PostID)
+		case api.SearchKindAgent:
+			return m.openProfile(hit.Agent.Name)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) applySearch(msg searchMsg) Model {
+	// A newer query replaced this one
+	if msg.query != m.searchQuery {
+		return m
+	}
+	m.isSearching = false
+	if msg.err != nil {
+		if msg.append {
+			m.message = "Failed to load more results: " + msg.err.Error()
+		} else {
+			m.err = msg.err
+		}
+		return m
+	}
+	if msg.append {
+		fresh := dedupeResults(m.searchResults, msg.results)
+		m.searchResults = append(m.searchResults, fresh...)
+		m.allSearchLoaded = len(msg.results) < searchPageSize || len(fresh) == 0
+	} else {
+		m.searchResults = msg.results
+		m.allSearchLoaded = len(msg.results) < searchPageSize
+	}
+	m.searchOffset += len(msg.results)
+	return m
+}
+
+func (m Model) searchView() string {
+	var s strings.Builder
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, TitleStyle.Render(" MOLTBOOK "), "  ", HeaderStyle.Render("SEARCH"), "  ", m.rateLimitView()))
+
+	var types []string
+	for _, t := range searchTypes {
+		if t == m.searchType {
+			types = append(types, lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Padding(0, 1).Render(t))
+		} else {
+			types = append(types, lipgloss.NewStyle().Foreground(GrayColor).Padding(0, 1).Render(t))
+		}
+	}
+	s.WriteString("\n" + m.textInput.View() + "\n" + strings.Join(types, " ") + "\n")
+
+	help := "enter: search • tab: type • esc: back"
+	if !m.searchFocused {
+		help = "j/k: select • enter: open • /: edit query • tab: type • esc: back • q: quit"
+	}
+	s.WriteString(HelpStyle.Render(help) + "\n\n")
+
+	switch {
+	case len(m.searchResults) == 0 && m.isSearching:
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("   %s Searching...", m.spinner.View())))
+	case len(m.searchResults) == 0 && m.searchQuery != "" && !m.searchFocused:
+		s.WriteString(lipgloss.NewStyle().Italic(true).Foreground(GrayColor).Render("No results for \"" + m.searchQuery + "\""))
+	}
+
+	// Keep the selection on screen; each card takes about four lines
+	rows := max((m.height-8)/4, 1)
+	start := max(m.searchIndex-rows+1, 0)
+	end := min(start+rows, len(m.searchResults))
+	for i := start; i < end; i++ {
+		s.WriteString(m.renderSearchResult(m.searchResults[i], i == m.searchIndex) + "\n")
+	}
+
+	if len(m.searchResults) > 0 && m.isSearching {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("   %s Loading...", m.spinner.View())))
+	}
+	if m.message != "" {
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(AccentColor).Render("• "+m.message))
+	}
+	return s.String()
+}
+
+func (m Model) renderSearchResult(hit api.SearchResult, selected bool) string {
+	style := PostCardStyle.MarginBottom(0)
+	if selected {
+		style = SelectedPostStyle.MarginBottom(0)
+	}
+
+	score := lipgloss.NewStyle().Foreground(AccentColor).Bold(true).Render(fmt.Sprintf("%3.0f%%", hit.Similarity*100))
+	meta := []string{score, strings.ToUpper(string(hit.Kind))}
+	var title string
+	switch hit.Kind {
+	case api.SearchKindComment:
+		title = hit.Comment.Content
+		meta = append(meta, AuthorStyle.Render(hit.Comment.Author.Name))
+	case api.SearchKindAgent:
+		title = hit.Agent.Name
+		meta = append(meta, fmt.Sprintf("%d karma", hit.Agent.Karma))
+		if hit.Agent.Description != "" {
+			meta = append(meta, hit.Agent.Description)
+		}
+	default:
+		title = hit.Post.Title
+		if title == "" {
+			title = hit.Post.Content
+		}
+		meta = append(meta, AuthorStyle.Render(hit.Post.Author.Name), SubmoltStyle.Render("m/"+hit.Post.Submolt.Name))
+	}
+	if len(title) > 80 {
+		title = title[:77] + "..."
+	}
+
+	return style.Width(m.width - 4).Render(lipgloss.NewStyle().Bold(true).Render(title) + "\n" + strings.Join(meta, " · "))
+}
+
+func (m Model) searchCmd(offset int) tea.Cmd {
+	ctx := m.requests.start(reqSearch)
+	query, searchType := m.searchQuery, m.searchType
+	return func() tea.Msg {
+		if m.client == nil {
+			return searchMsg{query: query, err: fmt.Errorf("client not initialized")}
+		}
+		results, err := m.client.Search(ctx, api.SearchQuery{Query: query, Type: searchType, Limit: searchPageSize, Offset: offset})
+		return searchMsg{query: query, results: results, err: err, append: offset > 0}
+	}
+}
+
+func (m Model) fetchSearchPostCmd(postID string) tea.Cmd {
+	ctx := m.requests.start(reqSearch)
+	return func() tea.Msg {
+		post, err := m.client.GetPost(ctx, postID)
+		return searchPostMsg{post: post, err: err}
+	}
+}
+
+// dedupeResults returns the hits in next that aren't already in have.
+func dedupeResults(have, next []api.SearchResult) []api.SearchResult {
+	seen := make(map[string]bool, len(have))
+	for _, r := range have {
+		seen[r.Key()] = true
+	}
+	var fresh []api.SearchResult
+	for _, r := range next {
+		if !seen[r.Key()] {
+			seen[r.Key()] = true
+			fresh = append(fresh, r)
+		}
+	}
+	return fresh
+}
@@ -44,6 +44,10 @@ var (
 			Foreground(PrimaryColor).
 			Bold(true)
 
+	LinkStyle = lipgloss.NewStyle().
+			Foreground(AccentColor).
+			Underline(true)
+
     HelpStyle = lipgloss.NewStyle().
             Foreground(GrayColor).
             Italic(true)
@@ -0,0 +1,346 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+// The API can't list an agent's subscriptions, only flag them in submolt
+// listings, so the picker keeps loading pages in the background to find
+// them. Submolts past the last page can still be picked by typing their
+// full name.
+const (
+	pickerPageSize = 100
+	pickerMaxPages = 10 // Bounds the reads spent on one picker
+)
+
+type pickerMsg struct {
+	submolts []api.Submolt
+	offset   int
+	err      error
+}
+
+// submoltCheckMsg reports whether a submolt typed into the picker exists.
+type submoltCheckMsg struct {
+	name    string
+	submolt *api.Submolt
+	err     error
+}
+
+// defaultSubmolt is where a new post goes unless another submolt is picked:
+// the submolt of the feed being read, or general.
+func (m Model) defaultSubmolt() string {
+	if m.feed.kind == feedSubmolt && m.feed.submolt != "" {
+		return m.feed.submolt
+	}
+	return "general"
+}
+
+// enterSubmoltStep opens the picker with the default submolt highlighted.
+func (m Model) enterSubmoltStep() (Model, tea.Cmd) {
+	m.createStep = postStepSubmolt
+	if m.newPostSubmolt == "" {
+		m.newPostSubmolt = m.defaultSubmolt()
+	}
+	m.pickerIndex = 0
+	m.isLoadingPicker = true
+	m.isCheckingSubmolt = false
+	m.textInput.Focus()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "type to filter"
+	return m, m.fetchPickerCmd(0)
+}
+
+func (m Model) updateSubmoltPicker(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if m.pickerIndex > 0 {
+				m.pickerIndex--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.pickerIndex < len(m.pickerMatches())-1 {
+				m.pickerIndex++
+			}
+			return m, nil
+		case "enter":
+			if m.isCheckingSubmolt {
+				return m, nil
+			}
+			name := normalizeSubmoltName(m.textInput.Value())
+			if matches := m.pickerMatches(); m.pickerIndex < len(matches) {
+				name = matches[m.pickerIndex].Name
+			}
+			if name == "" {
+				return m, nil
+			}
+			if m.pickerKnows(name) {
+				return m.chooseSubmolt(name), nil
+			}
+			// Typed or defaulted to a submolt we haven't seen; ask the server
+			m.message = ""
+			m.isCheckingSubmolt = true
+			return m, m.checkSubmoltCmd(name)
+		case "esc":
+			if m.textInput.Value() != "" {
+				m.textInput.SetValue("")
+				m.pickerIndex = 0
+				m.message = ""
+				return m, nil
+			}
+			return m.back()
+		}
+
+	case pickerMsg:
+		if isCanceled(msg.err) {
+			return m, nil
+		}
+		m.isLoadingPicker = false
+		if msg.err != nil {
+			// Names can still be typed and checked one by one
+			if msg.offset == 0 {
+				m.message = "Couldn't load submolts: " + msg.err.Error()
+			}
+			return m, nil
+		}
+		if msg.offset == 0 {
+			m.pickerSubmolts = msg.submolts
+			m.pickerIndex = 0
+		} else {
+			// Subscriptions sort ahead of what's shown, so keep the
+			// cursor on the same submolt
+			var highlighted string
+			if matches := m.pickerMatches(); m.pickerIndex < len(matches) {
+				highlighted = matches[m.pickerIndex].Name
+			}
+			for _, sm := range msg.submolts {
+				if !m.pickerKnows(sm.Name) {
+					m.pickerSubmolts = append(m.pickerSubmolts, sm)
+				}
+			}
+			for i, sm := range m.pickerMatches() {
+				if sm.Name == highlighted {
+					m.pickerIndex = i
+					break
+				}
+			}
+		}
+		next := msg.offset + len(msg.submolts)
+		if len(msg.submolts) == pickerPageSize && next < pickerPageSize*pickerMaxPages {
+			m.isLoadingPicker = true
+			return m, m.fetchPickerCmd(next)
+		}
+		return m, nil
+
+	case submoltCheckMsg:
+		if m.createStep != postStepSubmolt || isCanceled(msg.err) {
+			return m, nil
+		}
+		m.isCheckingSubmolt = false
+		if errors.Is(msg.err, api.ErrNotFound) {
+			m.message = fmt.Sprintf("m/%s doesn't exist", msg.name)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = "Couldn't check m/" + msg.name + ": " + msg.err.Error()
+			return m, nil
+		}
+		name := msg.name
+		if msg.submolt != nil && msg.submolt.Name != "" {
+			name = msg.submolt.Name
+		}
+		return m.chooseSubmolt(name), nil
+	}
+
+	before := m.textInput.Value()
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	if m.textInput.Value() != before {
+		m.pickerIndex = 0
+		m.message = ""
+	}
+	return m, cmd
+}
+
+// chooseSubmolt settles on name and moves on to the post kind.
+func (m Model) chooseSubmolt(name string) Model {
+	m.newPostSubmolt = name
+	m.message = ""
+	m.createStep = postStepKind
+	m.textInput.Blur()
+	m.textInput.SetValue("")
+	return m
+}
+
+// pickerKnows reports whether the server listed name, so it needn't be checked.
+func (m Model) pickerKnows(name string) bool {
+	for _, sm := range m.pickerSubmolts {
+		if sm.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pickerMatches lists the submolts to choose from. Without a filter the
+// current choice comes first, then subscriptions, then everything else;
+// with one they are ranked by how well they fuzzy-match it, after the typed
+// name itself when it isn't among the listed submolts.
+func (m Model) pickerMatches() []api.Submolt {
+	query := normalizeSubmoltName(m.textInput.Value())
+	if query == "" {
+		out := make([]api.Submolt, 0, len(m.pickerSubmolts)+1)
+		var rest []api.Submolt
+		for _, sm := range m.pickerSubmolts {
+			switch {
+			case sm.Name == m.newPostSubmolt:
+				out = append([]api.Submolt{sm}, out...)
+			case sm.IsSubscribed:
+				out = append(out, sm)
+			default:
+				rest = append(rest, sm)
+			}
+		}
+		if !m.pickerKnows(m.newPostSubmolt) && m.newPostSubmolt != "" {
+			out = append([]api.Submolt{{Name: m.newPostSubmolt}}, out...)
+		}
+		return append(out, rest...)
+	}
+
+	type match struct {
+		sm    api.Submolt
+		score int
+	}
+	var matches []match
+	for _, sm := range m.pickerSubmolts {
+		score, ok := fuzzyScore(query, sm.Name)
+		if s, ok2 := fuzzyScore(query, sm.DisplayName); ok2 && (!ok || s > score) {
+			score, ok = s, true
+		}
+		if !ok {
+			continue
+		}
+		if sm.IsSubscribed {
+			score++
+		}
+		matches = append(matches, match{sm, score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	var out []api.Submolt
+	if !m.pickerKnows(query) {
+		// The list only has the most popular submolts, so the typed name
+		// may still exist; enter checks it with the server
+		out = append(out, api.Submolt{Name: query})
+	}
+	for _, mt := range matches {
+		out = append(out, mt.sm)
+	}
+	return out
+}
+
+// fuzzyScore reports whether the runes of query appear in s in order, and
+// how well: runs of adjacent matches and matches at word starts score higher.
+func fuzzyScore(query, s string) (int, bool) {
+	target := []rune(strings.ToLower(s))
+	q := []rune(query)
+	if len(q) == 0 || len(target) == 0 {
+		return 0, false
+	}
+	score, qi, prev := 0, 0, -2
+	for i, r := range target {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(target[i-1]) && !unicode.IsDigit(target[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	if string(target) == query {
+		score += 100
+	}
+	return score, true
+}
+
+func (m Model) submoltPickerView() string {
+	var s strings.Builder
+	s.WriteString("Post to which submolt?\n")
+	s.WriteString(SubmoltStyle.Render("m/") + m.textInput.View() + "\n\n")
+
+	matches := m.pickerMatches()
+	if m.isLoadingPicker && len(m.pickerSubmolts) == 0 {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("%s Loading submolts...", m.spinner.View())) + "\n")
+	}
+
+	rows := min(max(m.height-16, 3), 10)
+	start := max(m.pickerIndex-rows+1, 0)
+	end := min(start+rows, len(matches))
+	for i := start; i < end; i++ {
+		sm := matches[i]
+		badge := "   "
+		if sm.IsSubscribed {
+			badge = lipgloss.NewStyle().Foreground(AccentColor).Render("[✓]")
+		}
+		name := SubmoltStyle.Render("m/" + sm.Name)
+		if i == m.pickerIndex {
+			name = lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Bold(true).Render("m/" + sm.Name)
+		}
+		line := badge + " " + name
+		switch {
+		case sm.DisplayName != "":
+			line += " " + HelpStyle.Render(sm.Title())
+		case !m.pickerKnows(sm.Name):
+			line += " " + HelpStyle.Render("not listed; enter checks it exists")
+		}
+		s.WriteString(line + "\n")
+	}
+	if len(matches) > end {
+		s.WriteString(HelpStyle.Render(fmt.Sprintf("    … %d more", len(matches)-end)) + "\n")
+	}
+	if m.isCheckingSubmolt {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("%s Checking submolt...", m.spinner.View())) + "\n")
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+func (m Model) fetchPickerCmd(offset int) tea.Cmd {
+	ctx := m.requests.start(reqPicker)
+	return func() tea.Msg {
+		if m.client == nil {
+			return pickerMsg{offset: offset, err: fmt.Errorf("client not initialized")}
+		}
+		submolts, err := m.client.ListSubmolts(ctx, api.SubmoltSortPopular, pickerPageSize, offset)
+		return pickerMsg{submolts: submolts, offset: offset, err: err}
+	}
+}
+
+func (m Model) checkSubmoltCmd(name string) tea.Cmd {
+	ctx := m.requests.start(reqSubmoltCheck)
+	return func() tea.Msg {
+		if m.client == nil {
+			return submoltCheckMsg{name: name, err: fmt.Errorf("client not initialized")}
+		}
+		sm, err := m.client.GetSubmolt(ctx, name)
+		return submoltCheckMsg{name: name, submolt: sm, err: err}
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/starkbaknet/moltbook-client/pkg/api"
+)
+
+const submoltPageSize = 50
+
+type submoltsMsg struct {
+	submolts []api.Submolt
+	err      error
+	append   bool
+}
+
+type subscribeMsg struct {
+	name       string
+	subscribed bool
+	err        error
+}
+
+// openSubmolts switches to the directory, loading the first page.
+func (m Model) openSubmolts() (Model, tea.Cmd) {
+	m = m.push()
+	m.state = stateSubmolts
+	m.submolts = nil
+	m.submoltIndex = 0
+	m.allSubmoltsLoaded = false
+	m.isLoadingSubmolts = true
+	m.isLoading = false
+	m.message = ""
+	return m, m.fetchSubmoltsCmd(0)
+}
+
+func (m Model) updateSubmolts(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down":
+			if m.submoltIndex < len(m.submolts)-1 {
+				m.submoltIndex++
+				if m.submoltIndex >= len(m.submolts)-2 && !m.isLoadingSubmolts && !m.allSubmoltsLoaded {
+					m.isLoadingSubmolts = true
+					return m, m.fetchSubmoltsCmd(len(m.submolts))
+				}
+			}
+		case "k", "up":
+			if m.submoltIndex > 0 {
+				m.submoltIndex--
+			}
+		case "s", " ":
+			if sm := m.highlightedSubmolt(); sm != nil {
+				return m, m.subscribeCmd(sm.Name, !sm.IsSubscribed)
+			}
+		case "enter":
+			if sm := m.highlightedSubmolt(); sm != nil {
+				return m.openFeed(submoltFeed(sm.Name))
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) highlightedSubmolt() *api.Submolt {
+	if m.submoltIndex < 0 || m.submoltIndex >= len(m.submolts) {
+		return nil
+	}
+	return &m.submolts[m.submoltIndex]
+}
+
+// applySubscription reflects a finished subscribe toggle in the list.
+func (m Model) applySubscription(msg subscribeMsg) Model {
+	for i := range m.submolts {
+		if m.submolts[i].Name != msg.name || m.submolts[i].IsSubscribed == msg.subscribed {
+			continue
+		}
+		m.submolts[i].IsSubscribed = msg.subscribed
+		if msg.subscribed {
+			m.submolts[i].SubscriberCount++
+		} else if m.submolts[i].SubscriberCount > 0 {
+			m.submolts[i].SubscriberCount--
+		}
+	}
+	return m
+}
+
+func (m Model) submoltsView() string {
+	var s strings.Builder
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, TitleStyle.Render(" MOLTBOOK "), "  ", HeaderStyle.Render("SUBMOLTS")))
+	s.WriteString("\n" + HelpStyle.Render("j/k: select • enter: open feed • s/space: subscribe • esc: back • q: quit"))
+	s.WriteString("\n\n")
+
+	if len(m.submolts) == 0 {
+		if m.isLoadingSubmolts {
+			s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("   %s Loading submolts...", m.spinner.View())))
+		} else {
+			s.WriteString("No submolts found.")
+		}
+		return s.String()
+	}
+
+	// Keep the selection on screen; each row takes two lines
+	rows := max((m.height-6)/2, 1)
+	start := max(m.submoltIndex-rows+1, 0)
+	end := min(start+rows, len(m.submolts))
+
+	for i := start; i < end; i++ {
+		sm := m.submolts[i]
+		badge := lipgloss.NewStyle().Foreground(GrayColor).Render("[ ]")
+		if sm.IsSubscribed {
+			badge = lipgloss.NewStyle().Foreground(AccentColor).Render("[✓]")
+		}
+		name := SubmoltStyle.Render("m/" + sm.Name)
+		if i == m.submoltIndex {
+			name = lipgloss.NewStyle().Foreground(BaseColor).Background(PrimaryColor).Bold(true).Render("m/" + sm.Name)
+		}
+		line := fmt.Sprintf("%s %s %s", badge, name, HelpStyle.Render(fmt.Sprintf("%s · %d subscribers", sm.Title(), sm.SubscriberCount)))
+		desc := sm.Description
+		if width := m.width - 8; width > 3 && len(desc) > width {
+			desc = desc[:width-3] + "..."
+		}
+		s.WriteString(line + "\n    " + desc + "\n")
+	}
+
+	if m.isLoadingSubmolts {
+		s.WriteString(lipgloss.NewStyle().Foreground(AccentColor).Render(fmt.Sprintf("\n   %s Loading...", m.spinner.View())))
+	}
+	if m.message != "" {
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(AccentColor).Render("• "+m.message))
+	}
+	return s.String()
+}
+
+func (m Model) fetchSubmoltsCmd(offset int) tea.Cmd {
+	ctx := m.requests.start(reqSubmolts)
+	return func() tea.Msg {
+		if m.client == nil {
+			return submoltsMsg{err: fmt.Errorf("client not initialized")}
+		}
+		submolts, err := m.client.ListSubmolts(ctx, api.SubmoltSortPopular, submoltPageSize, offset)
+		return submoltsMsg{submolts: submolts, err: err, append: offset > 0}
+	}
+}
+
+func (m Model) subscribeCmd(name string, subscribe bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if subscribe {
+			err = m.client.Subscribe(context.Background(), name)
+		} else {
+			err = m.client.Unsubscribe(context.Background(), name)
+		}
+		return subscribeMsg{name: name, subscribed: subscribe, err: err}
+	}
+}
+
+// startJumpSubmolt prompts for a submolt name to open.
+func (m Model) startJumpSubmolt() Model {
+	m = m.push()
+	m.state = stateJumpSubmolt
+	m.message = ""
+	m.textInput.Focus()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "submolt name"
+	return m
+}
+
+func (m Model) updateJumpSubmolt(msg tea.Msg) (Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "enter" {
+		name := normalizeSubmoltName(m.textInput.Value())
+		if name == "" {
+			return m, nil
+		}
+		// The prompt itself isn't somewhere back should return to
+		return m.pop().openFeed(submoltFeed(name))
+	}
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) jumpSubmoltView() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		TitleStyle.Render(" GO TO SUBMOLT "),
+		"",
+		SubmoltStyle.Render("m/")+m.textInput.View(),
+		"\n"+HelpStyle.Render("enter: open • esc: cancel"),
+	)
+}